@@ -9,7 +9,7 @@ import (
 )
 
 func logError(ginCtx *gin.Context, err error) {
-	logger := LoggerServiceInstance()
+	logger := appFromContext(ginCtx).Logger()
 
 	req := ginCtx.Request
 	cleanHeaders := cleanRequestHeaders(req.Header.Clone())
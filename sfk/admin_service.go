@@ -0,0 +1,212 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/omkarsrepo/server-framework/sfk/boom"
+	"github.com/omkarsrepo/server-framework/sfk/json"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"net"
+	"net/http"
+	"time"
+)
+
+type AdminService interface {
+	enabled() bool
+	registerRoutes(enablePprof bool)
+	start(restart GracefulRestartService, httpServer HTTPServerService)
+	shutdown(ctx context.Context) error
+}
+
+type logLevelRequest struct {
+	Component string `json:"component"`
+	Level     string `json:"level" binding:"required"`
+	Duration  string `json:"duration"`
+}
+
+type adminService struct {
+	app       *App
+	isEnabled bool
+	config    ConfigService
+	logger    *zerolog.Logger
+	router    *gin.Engine
+	server    *http.Server
+}
+
+func newAdminService(app *App, enable bool) AdminService {
+	config := app.Config()
+
+	return &adminService{
+		app:       app,
+		isEnabled: enable || config.GetBool("admin.enabled"),
+		config:    config,
+		logger:    app.Logger().ZeroLogger(),
+		router:    gin.New(),
+	}
+}
+
+func authorizeWithSecret(secretService SecretService, secretKey string) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		authToken, exp := json.ExtractAuthorization(ginCtx)
+		if exp != nil {
+			Abort(ginCtx, exp)
+			return
+		}
+
+		val, exp := secretService.ValueOf(secretKey)
+		if exp != nil {
+			Abort(ginCtx, exp)
+			return
+		}
+
+		if authToken != val {
+			Abort(ginCtx, boom.Unauthorized("Invalid authToken for authorization header"))
+			return
+		}
+
+		ginCtx.Next()
+	}
+}
+
+func (a *adminService) enabled() bool {
+	return a.isEnabled
+}
+
+func (a *adminService) address() string {
+	host := a.config.GetString("admin.host")
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	port := a.config.GetString("admin.port")
+	if port == "" {
+		port = "8084"
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
+func (a *adminService) registerRoutes(enablePprof bool) {
+	a.router.Use(gin.Recovery(), applyApp(a.app))
+
+	registerHealthEndpoints(a.router, a.app.Health())
+
+	protected := a.router.Group("/admin", authorizeWithSecret(a.app.Secrets(), "pprofSecret"))
+	protected.GET("/config", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, redactSettings(a.config.GetViper().AllSettings()))
+	})
+	protected.GET("/cache", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, a.app.cacheService().stats())
+	})
+	protected.DELETE("/cache", func(ginCtx *gin.Context) {
+		a.app.cacheService().purge()
+		ginCtx.Status(http.StatusNoContent)
+	})
+	protected.GET("/featureFlags", func(ginCtx *gin.Context) {
+		flags := a.app.FeatureFlags().Flags()
+
+		key := ginCtx.Query("key")
+		if key == "" {
+			ginCtx.JSON(http.StatusOK, gin.H{"flags": flags})
+			return
+		}
+
+		evaluations := lo.Map(flags, func(flag FeatureFlag, _ int) FeatureFlagEvaluation {
+			return a.app.FeatureFlags().Evaluate(flag.Name, key)
+		})
+
+		ginCtx.JSON(http.StatusOK, gin.H{"flags": flags, "key": key, "evaluations": evaluations})
+	})
+	protected.GET("/logLevels", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, a.app.Logger().Levels())
+	})
+	protected.PUT("/logLevels", a.setLogLevel)
+	protected.GET("/logSinks", func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, gin.H{"sinks": a.app.Logger().SinkStats()})
+	})
+	protected.DELETE("/logLevels", func(ginCtx *gin.Context) {
+		component := ginCtx.Query("component")
+		if !a.app.Logger().ResetLevel(component) {
+			Abort(ginCtx, boom.NotFound(fmt.Sprintf("No log level override for %s", logLevelTarget(component))))
+			return
+		}
+
+		a.logger.Info().Msgf("Log level override of %s removed", logLevelTarget(component))
+		ginCtx.JSON(http.StatusOK, a.app.Logger().Levels())
+	})
+
+	if enablePprof {
+		registerPprof(a.router, a.app.Secrets())
+	}
+}
+
+func logLevelTarget(component string) string {
+	if component == "" {
+		return "the default logger"
+	}
+
+	return fmt.Sprintf("component '%s'", component)
+}
+
+func (a *adminService) setLogLevel(ginCtx *gin.Context) {
+	var request logLevelRequest
+	if err := ginCtx.ShouldBindJSON(&request); err != nil {
+		AbortForValidation(ginCtx, err)
+		return
+	}
+
+	var duration time.Duration
+	if request.Duration != "" {
+		parsed, err := time.ParseDuration(request.Duration)
+		if err != nil || parsed < 0 {
+			Abort(ginCtx, boom.BadRequest(fmt.Sprintf(`Invalid duration "%s", e.g. "15m"`, request.Duration)))
+			return
+		}
+
+		duration = parsed
+	}
+
+	if err := a.app.Logger().SetLevel(request.Component, request.Level, duration); err != nil {
+		Abort(ginCtx, boom.BadRequest(err.Error()))
+		return
+	}
+
+	until := "until reset"
+	if duration > 0 {
+		until = "for " + duration.String()
+	}
+
+	a.logger.Info().Msgf("Log level of %s set to %s %s", logLevelTarget(request.Component), request.Level, until)
+	ginCtx.JSON(http.StatusOK, a.app.Logger().Levels())
+}
+
+func (a *adminService) start(restart GracefulRestartService, httpServer HTTPServerService) {
+	a.server = httpServer.newServer(a.address(), a.router.Handler())
+	a.server.WriteTimeout = 0
+
+	listener, err := restart.listen("tcp", a.server.Addr, false)
+	if err != nil {
+		a.logger.Fatal().Msgf("Failed to start admin server... listen: %+v\n", err)
+	}
+
+	go func() {
+		a.logger.Info().Msgf("Admin server running successfully on %s", a.server.Addr)
+
+		if err := a.server.Serve(httpServer.wrapListener(listener)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.logger.Fatal().Msgf("Failed to start admin server... listen: %+v\n", err)
+		}
+	}()
+}
+
+func (a *adminService) shutdown(ctx context.Context) error {
+	if a.server == nil {
+		return nil
+	}
+
+	return a.server.Shutdown(ctx)
+}
@@ -0,0 +1,297 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"github.com/spf13/viper"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+const appContextKey = "SFK_APP"
+
+var (
+	defaultApp        *App
+	defaultAppOnce    sync.Once
+	defaultAppClaimed atomic.Bool
+	activeApp         atomic.Pointer[App]
+	activeAppMtx      sync.Mutex
+)
+
+type AppOptions struct {
+	Config        map[string]any
+	SecretService SecretService
+	LogWriter     io.Writer
+	ConfigSources []types.ConfigSource
+}
+
+// App owns the services of one server: config, logger, router, cache, secrets, health, lifecycle and
+// feature flags, each created on first use. The first NewServerService gets the DefaultApp behind the
+// package-level accessors such as ConfigServiceInstance and RouterInstance, later servers in the same
+// process get isolated Apps. While a server runs its Routes, Database and start hooks the package-level
+// accessors resolve to its App, so resolve services there rather than per request.
+type App struct {
+	viper         *viper.Viper
+	viperSetupMtx sync.Mutex
+	viperSetup    []func(v *viper.Viper)
+	envPrefix     string
+	setOverrides  func() []string
+	configSources []types.ConfigSource
+	options       *AppOptions
+	configOnce    sync.Once
+	config        *configService
+	loggerOnce    sync.Once
+	logger        *loggerService
+	routerOnce    sync.Once
+	router        RouterService
+	cacheOnce     sync.Once
+	cache         *cacheService
+	secretsOnce   sync.Once
+	secrets       SecretService
+	healthOnce    sync.Once
+	health        *healthRegistry
+	lifecycleOnce sync.Once
+	lifecycle     *lifecycleService
+	featuresOnce  sync.Once
+	features      FeatureFlags
+	bindingsMtx   sync.Mutex
+	bindings      []configBinding
+	keysMtx       sync.Mutex
+	keys          []types.ConfigKey
+}
+
+func newApp(v *viper.Viper, options *AppOptions) *App {
+	if options == nil {
+		options = &AppOptions{}
+	}
+
+	app := &App{viper: v, options: options, configSources: options.ConfigSources}
+	app.configureViper(func(v *viper.Viper) {
+		v.SetDefault("env", "sandbox")
+	})
+
+	return app
+}
+
+func NewApp() *App {
+	return newApp(viper.New(), nil)
+}
+
+func NewAppWithOptions(options *AppOptions) *App {
+	return newApp(viper.New(), options)
+}
+
+func DefaultApp() *App {
+	defaultAppOnce.Do(func() {
+		defaultApp = newApp(viper.GetViper(), nil)
+	})
+
+	return defaultApp
+}
+
+func currentApp() *App {
+	if app := activeApp.Load(); app != nil {
+		return app
+	}
+
+	return DefaultApp()
+}
+
+func (a *App) activate(run func()) {
+	if a == DefaultApp() {
+		run()
+		return
+	}
+
+	activeAppMtx.Lock()
+	defer activeAppMtx.Unlock()
+
+	activeApp.Store(a)
+	defer activeApp.Store(nil)
+
+	run()
+}
+
+func claimApp() *App {
+	if defaultAppClaimed.CompareAndSwap(false, true) {
+		return DefaultApp()
+	}
+
+	return NewApp()
+}
+
+func appFromContext(ginCtx *gin.Context) *App {
+	if ginCtx != nil {
+		if app, ok := ginCtx.Get(appContextKey); ok {
+			return app.(*App)
+		}
+	}
+
+	return currentApp()
+}
+
+func applyApp(app *App) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		ginCtx.Set(appContextKey, app)
+
+		ginCtx.Next()
+	}
+}
+
+func (a *App) Viper() *viper.Viper {
+	return a.Config().GetViper()
+}
+
+func (a *App) configureViper(setup func(v *viper.Viper)) {
+	a.viperSetupMtx.Lock()
+	defer a.viperSetupMtx.Unlock()
+
+	setup(a.viper)
+	a.viperSetup = append(a.viperSetup, setup)
+}
+
+// viperSetups returns the setup replayed on every reloaded config, including setup added after the
+// config was first loaded.
+func (a *App) viperSetups() []func(v *viper.Viper) {
+	a.viperSetupMtx.Lock()
+	defer a.viperSetupMtx.Unlock()
+
+	return slices.Clone(a.viperSetup)
+}
+
+func (a *App) addConfigSources(sources ...types.ConfigSource) {
+	if len(sources) == 0 {
+		return
+	}
+
+	if a.config != nil {
+		panic("ServerOptions.ConfigSources cannot be applied, the config was already loaded before NewServerService. " +
+			"Pass them through AppOptions.ConfigSources or create the server before reading the config.")
+	}
+
+	a.configSources = append(a.configSources, sources...)
+}
+
+func (a *App) configOverrides() []string {
+	if a.setOverrides == nil {
+		return nil
+	}
+
+	return a.setOverrides()
+}
+
+func (a *App) configService() *configService {
+	a.configOnce.Do(func() {
+		a.config = newConfigService(a)
+	})
+
+	return a.config
+}
+
+func (a *App) Config() ConfigService {
+	return a.configService()
+}
+
+func (a *App) loggerService() *loggerService {
+	a.loggerOnce.Do(func() {
+		a.logger = newLoggerService(a.Config(), a.options.LogWriter)
+	})
+
+	return a.logger
+}
+
+func (a *App) Logger() LoggerService {
+	return a.loggerService()
+}
+
+func (a *App) Router() RouterService {
+	a.routerOnce.Do(func() {
+		a.router = newRouterService(a.Config())
+	})
+
+	return a.router
+}
+
+func (a *App) cacheService() *cacheService {
+	a.cacheOnce.Do(func() {
+		a.cache = newCacheService()
+	})
+
+	return a.cache
+}
+
+func (a *App) Cache() CacheService {
+	return a.cacheService()
+}
+
+func (a *App) Secrets() SecretService {
+	a.secretsOnce.Do(func() {
+		a.secrets = a.options.SecretService
+		if a.secrets == nil {
+			a.secrets = newSecretService(a.Config(), a.Logger(), a.Cache())
+		}
+	})
+
+	return a.secrets
+}
+
+func (a *App) healthRegistry() *healthRegistry {
+	a.healthOnce.Do(func() {
+		a.health = newHealthRegistry()
+	})
+
+	return a.health
+}
+
+func (a *App) Health() HealthRegistry {
+	return a.healthRegistry()
+}
+
+func (a *App) lifecycleService() *lifecycleService {
+	a.lifecycleOnce.Do(func() {
+		a.lifecycle = newLifecycleService(a.Logger())
+	})
+
+	return a.lifecycle
+}
+
+func (a *App) Lifecycle() LifecycleService {
+	return a.lifecycleService()
+}
+
+func (a *App) FeatureFlags() FeatureFlags {
+	a.featuresOnce.Do(func() {
+		a.features = newFeatureFlags(a.Config(), a.Logger())
+	})
+
+	return a.features
+}
+
+func (a *App) registerConfigBinding(binding configBinding) {
+	a.bindingsMtx.Lock()
+	defer a.bindingsMtx.Unlock()
+
+	a.bindings = append(a.bindings, binding)
+}
+
+func (a *App) validateConfig(config ConfigService) error {
+	a.bindingsMtx.Lock()
+	bindings := append([]configBinding(nil), a.bindings...)
+	a.bindingsMtx.Unlock()
+
+	errs := make([]error, 0, len(bindings))
+	for _, binding := range bindings {
+		errs = append(errs, binding.validate(config))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *App) ValidateConfig() error {
+	return a.validateConfig(a.Config())
+}
@@ -9,42 +9,43 @@ import (
 	"time"
 )
 
-var (
-	cacheServiceInstance *cacheService
-	cacheServiceOnce     sync.Once
-	cacheMapsMtx         sync.RWMutex
-	variableCacheMapsMtx sync.RWMutex
-)
-
 type CacheService interface {
 	New(capacity int, ttl time.Duration) otter.Cache[string, any]
 	NewVariable(capacity int) otter.CacheWithVariableTTL[string, any]
 	Close()
 }
 
+type CacheStats struct {
+	Size        int  `json:"size"`
+	Capacity    int  `json:"capacity"`
+	VariableTTL bool `json:"variableTtl"`
+}
+
 type cacheService struct {
-	cacheMaps         []otter.Cache[string, any]
-	variableCacheMaps []otter.CacheWithVariableTTL[string, any]
+	cacheMaps            []otter.Cache[string, any]
+	variableCacheMaps    []otter.CacheWithVariableTTL[string, any]
+	cacheMapsMtx         sync.RWMutex
+	variableCacheMapsMtx sync.RWMutex
 }
 
-func Cache() CacheService {
-	cacheServiceOnce.Do(func() {
-		cacheServiceInstance = &cacheService{}
-	})
+func newCacheService() *cacheService {
+	return &cacheService{}
+}
 
-	return cacheServiceInstance
+func Cache() CacheService {
+	return currentApp().Cache()
 }
 
 func (c *cacheService) registerCache(cache otter.Cache[string, any]) {
-	cacheMapsMtx.Lock()
-	defer cacheMapsMtx.Unlock()
+	c.cacheMapsMtx.Lock()
+	defer c.cacheMapsMtx.Unlock()
 
 	c.cacheMaps = append(c.cacheMaps, cache)
 }
 
 func (c *cacheService) registerVariableCache(cache otter.CacheWithVariableTTL[string, any]) {
-	variableCacheMapsMtx.Lock()
-	defer variableCacheMapsMtx.Unlock()
+	c.variableCacheMapsMtx.Lock()
+	defer c.variableCacheMapsMtx.Unlock()
 
 	c.variableCacheMaps = append(c.variableCacheMaps, cache)
 }
@@ -75,16 +76,50 @@ func (c *cacheService) NewVariable(capacity int) otter.CacheWithVariableTTL[stri
 	return cache
 }
 
+func (c *cacheService) stats() []CacheStats {
+	c.cacheMapsMtx.RLock()
+	defer c.cacheMapsMtx.RUnlock()
+
+	stats := lo.Map(c.cacheMaps, func(cache otter.Cache[string, any], _ int) CacheStats {
+		return CacheStats{Size: cache.Size(), Capacity: cache.Capacity()}
+	})
+
+	c.variableCacheMapsMtx.RLock()
+	defer c.variableCacheMapsMtx.RUnlock()
+
+	variableStats := lo.Map(c.variableCacheMaps, func(cache otter.CacheWithVariableTTL[string, any], _ int) CacheStats {
+		return CacheStats{Size: cache.Size(), Capacity: cache.Capacity(), VariableTTL: true}
+	})
+
+	return append(stats, variableStats...)
+}
+
+func (c *cacheService) purge() {
+	c.cacheMapsMtx.RLock()
+	defer c.cacheMapsMtx.RUnlock()
+
+	lo.ForEach(c.cacheMaps, func(cache otter.Cache[string, any], _ int) {
+		cache.Clear()
+	})
+
+	c.variableCacheMapsMtx.RLock()
+	defer c.variableCacheMapsMtx.RUnlock()
+
+	lo.ForEach(c.variableCacheMaps, func(cache otter.CacheWithVariableTTL[string, any], _ int) {
+		cache.Clear()
+	})
+}
+
 func (c *cacheService) Close() {
-	cacheMapsMtx.RLock()
-	defer cacheMapsMtx.RUnlock()
+	c.cacheMapsMtx.RLock()
+	defer c.cacheMapsMtx.RUnlock()
 
 	lo.ForEach(c.cacheMaps, func(cache otter.Cache[string, any], _ int) {
 		cache.Close()
 	})
 
-	variableCacheMapsMtx.RLock()
-	defer variableCacheMapsMtx.RUnlock()
+	c.variableCacheMapsMtx.RLock()
+	defer c.variableCacheMapsMtx.RUnlock()
 
 	lo.ForEach(c.variableCacheMaps, func(cache otter.CacheWithVariableTTL[string, any], _ int) {
 		cache.Close()
@@ -13,12 +13,16 @@ type CommandsService interface {
 }
 
 type commandsService struct {
-	cmd *cobra.Command
+	cmd       *cobra.Command
+	app       *App
+	envPrefix string
 }
 
-func newCommandsService(command *cobra.Command) CommandsService {
+func newCommandsService(command *cobra.Command, app *App, envPrefix string) CommandsService {
 	return &commandsService{
-		cmd: command,
+		cmd:       command,
+		app:       app,
+		envPrefix: normalizeEnvPrefix(envPrefix),
 	}
 }
 
@@ -29,35 +33,59 @@ func (c *commandsService) markRequiredFlags(flags ...string) {
 }
 
 func (c *commandsService) register() {
-	c.cmd.PersistentFlags().String("env", "sandbox", `env of server, can be "prod", "sandbox", "devl" or "localhost"`)
+	c.cmd.PersistentFlags().String("env", "sandbox", `env of server, e.g. "prod", "sandbox", "devl" or "localhost", loads <configDir>/<env> config file`)
 	c.cmd.PersistentFlags().Int("port", 8083, "port of server")
 	c.cmd.PersistentFlags().Int("gracefulShutdownSecs", 1, "graceful shutdown secs for server")
+	c.cmd.PersistentFlags().String("configDir", defaultConfigDir, `directory containing <env>.json|yaml|yml|toml config files, can also be set with CONFIG_DIR`)
+	c.cmd.PersistentFlags().StringArray("set", nil, `override a config key, e.g. --set rateLimitCallsPerSec=50 --set 'listen=[":8083"]', can be repeated`)
 
 	c.markRequiredFlags()
 }
 
-func (c *commandsService) bindToConfig() {
-	viper.SetDefault("env", "sandbox")
+func (c *commandsService) bindToConfig(v *viper.Viper) {
+	v.SetDefault("env", "sandbox")
 
-	err := viper.BindPFlag("env", c.cmd.PersistentFlags().Lookup("env"))
+	err := v.BindPFlag("env", c.cmd.PersistentFlags().Lookup("env"))
 	if err != nil {
 		panic(err)
 	}
 
-	err = viper.BindPFlag("port", c.cmd.PersistentFlags().Lookup("port"))
+	err = v.BindPFlag("port", c.cmd.PersistentFlags().Lookup("port"))
 	if err != nil {
 		panic(err)
 	}
 
-	err = viper.BindPFlag("gracefulShutdownSecs", c.cmd.PersistentFlags().Lookup("gracefulShutdownSecs"))
+	err = v.BindPFlag("gracefulShutdownSecs", c.cmd.PersistentFlags().Lookup("gracefulShutdownSecs"))
 	if err != nil {
 		panic(err)
 	}
 
-	viper.AutomaticEnv()
+	err = v.BindPFlag("configDir", c.cmd.PersistentFlags().Lookup("configDir"))
+	if err != nil {
+		panic(err)
+	}
+
+	err = v.BindEnv("configDir", "CONFIG_DIR")
+	if err != nil {
+		panic(err)
+	}
+
+	if c.envPrefix == "" {
+		return
+	}
+
+	v.SetEnvPrefix(c.envPrefix)
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	v.AutomaticEnv()
+	bindPrefixedEnv(v, c.envPrefix)
 }
 
 func (c *commandsService) registerCommands() {
 	c.register()
-	c.bindToConfig()
+	c.app.envPrefix = c.envPrefix
+	c.app.setOverrides = func() []string {
+		overrides, _ := c.cmd.PersistentFlags().GetStringArray("set")
+		return overrides
+	}
+	c.app.configureViper(c.bindToConfig)
 }
@@ -0,0 +1,248 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	configValidatorInstance *validator.Validate
+	configValidatorOnce     sync.Once
+)
+
+type ConfigValidationError struct {
+	Prefix string
+	Issues []string
+}
+
+func (e *ConfigValidationError) Error() string {
+	section := lo.Ternary(e.Prefix == "", "", " for "+e.Prefix)
+
+	return fmt.Sprintf("invalid config%s:\n  - %s", section, strings.Join(e.Issues, "\n  - "))
+}
+
+type configBinding struct {
+	prefix   string
+	validate func(config ConfigService) error
+}
+
+func configValidator() *validator.Validate {
+	configValidatorOnce.Do(func() {
+		configValidatorInstance = validator.New(validator.WithRequiredStructEnabled())
+		configValidatorInstance.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if name == "" || name == "-" {
+				return field.Name
+			}
+
+			return name
+		})
+
+		err := configValidatorInstance.RegisterValidation("notBlank", (&customValidatorsService{}).notBlank)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	return configValidatorInstance
+}
+
+func configKey(prefix, structName string, fieldError validator.FieldError) string {
+	path := strings.TrimPrefix(strings.TrimPrefix(fieldError.Namespace(), structName), ".")
+	if prefix == "" {
+		return path
+	}
+
+	return prefix + "." + path
+}
+
+func validationIssues(config ConfigService, prefix, structName string, err error) []string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+
+	return lo.Map(validationErrors, func(fieldError validator.FieldError, _ int) string {
+		key := configKey(prefix, structName, fieldError)
+		value := fieldError.Value()
+		if sensitiveKeys.MatchString(key) || isSecretReference(config.GetViper().Get(key)) {
+			value = redactedValue
+		}
+
+		if fieldError.Tag() == "required" && !config.GetViper().IsSet(key) {
+			return fmt.Sprintf("key '%s' is missing", key)
+		}
+
+		if fieldError.Param() != "" {
+			return fmt.Sprintf("key '%s' with value '%v' failed constraint '%s=%s'", key, value, fieldError.Tag(), fieldError.Param())
+		}
+
+		return fmt.Sprintf("key '%s' with value '%v' failed constraint '%s'", key, value, fieldError.Tag())
+	})
+}
+
+func configSection(settings map[string]any, prefix string) any {
+	var section any = settings
+
+	for _, part := range strings.Split(strings.ToLower(prefix), ".") {
+		nested, ok := section.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		section = nested[part]
+	}
+
+	return section
+}
+
+func durationStringHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != durationType {
+		return data, nil
+	}
+
+	switch from.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if cast.ToFloat64(data) != 0 {
+			return nil, fmt.Errorf(`expected a duration string such as "20s", got the bare number %v`, data)
+		}
+	}
+
+	return data, nil
+}
+
+// commaSeparatedHook splits strings bound to slices like GetStringSlice, trimming the items.
+func commaSeparatedHook(from reflect.Kind, to reflect.Kind, data any) (any, error) {
+	if from != reflect.String || to != reflect.Slice {
+		return data, nil
+	}
+
+	return configStringSlice(data), nil
+}
+
+// configDuration reads a config value as a duration by the rules of the typed bindings, so a bare
+// non-zero number is invalid instead of a count of nanoseconds.
+func configDuration(value any) (time.Duration, bool) {
+	if value == nil {
+		return 0, true
+	}
+
+	if _, err := durationStringHook(reflect.TypeOf(value), durationType, value); err != nil {
+		return 0, false
+	}
+
+	duration, err := cast.ToDurationE(value)
+
+	return duration, err == nil
+}
+
+func decodeIssues(err error) []string {
+	var decodeError *mapstructure.Error
+	if errors.As(err, &decodeError) {
+		return decodeError.Errors
+	}
+
+	return []string{err.Error()}
+}
+
+type configDecoder interface {
+	decodeHook(resolveSecrets bool) mapstructure.DecodeHookFunc
+}
+
+func configDecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.DecodeHookFuncKind(structuredValueHook),
+		mapstructure.DecodeHookFuncType(durationStringHook),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.DecodeHookFuncKind(commaSeparatedHook),
+	)
+}
+
+func decodeHookFor(config ConfigService, resolveSecrets bool) mapstructure.DecodeHookFunc {
+	if decoder, ok := config.(configDecoder); ok {
+		return decoder.decodeHook(resolveSecrets)
+	}
+
+	return configDecodeHook()
+}
+
+func bindConfig[T any](config ConfigService, prefix string, resolveSecrets bool) (T, error) {
+	var target T
+
+	var section any = config.GetViper().AllSettings()
+	if prefix != "" {
+		section = configSection(section.(map[string]any), prefix)
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           &target,
+		WeaklyTypedInput: true,
+		DecodeHook:       decodeHookFor(config, resolveSecrets),
+	})
+	if err == nil {
+		err = decoder.Decode(section)
+	}
+
+	if err != nil {
+		return target, &ConfigValidationError{Prefix: prefix, Issues: decodeIssues(err)}
+	}
+
+	if err := configValidator().Struct(&target); err != nil {
+		return target, &ConfigValidationError{Prefix: prefix, Issues: validationIssues(config, prefix, reflect.TypeOf(target).Name(), err)}
+	}
+
+	return target, nil
+}
+
+// Bind unmarshals the config section at prefix of the current App into T using mapstructure tags and
+// validates it with validate tags, including notBlank. The error is a ConfigValidationError listing every
+// missing or invalid key. Durations must be strings with a unit, lists may be JSON or comma separated.
+func Bind[T any](prefix string) (T, error) {
+	return BindConfig[T](currentApp().Config(), prefix)
+}
+
+// MustBind is Bind but panics on invalid config.
+func MustBind[T any](prefix string) T {
+	return MustBindConfig[T](currentApp().Config(), prefix)
+}
+
+// BindConfig is Bind for the given ConfigService.
+func BindConfig[T any](config ConfigService, prefix string) (T, error) {
+	return bindConfig[T](config, prefix, true)
+}
+
+// MustBindConfig is BindConfig but panics on invalid config.
+func MustBindConfig[T any](config ConfigService, prefix string) T {
+	target, err := BindConfig[T](config, prefix)
+	if err != nil {
+		panic(err)
+	}
+
+	return target
+}
+
+// RegisterConfig declares the keys of T and validates the section at startup, on every config reload and in
+// config validate, also when registered from the Routes or Database hooks. The framework registers its own
+// settings this way; hashicorp.* and clientIds.hashicorp are only required once the secret service is used.
+func RegisterConfig[T any](app *App, prefix string) {
+	app.DeclareConfigKeys(structConfigKeys(prefix, reflect.TypeFor[T]())...)
+	app.registerConfigBinding(configBinding{
+		prefix: prefix,
+		validate: func(config ConfigService) error {
+			_, err := BindConfig[T](config, prefix)
+			return err
+		},
+	})
+}
@@ -0,0 +1,106 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testClientConfig struct {
+	BaseURL  string        `mapstructure:"baseUrl" validate:"required,url"`
+	Token    string        `mapstructure:"token" validate:"notBlank"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Retries  int           `mapstructure:"retries" validate:"gte=0,lte=5"`
+	Scopes   []string      `mapstructure:"scopes"`
+	Optional string        `mapstructure:"optional"`
+}
+
+func TestBindConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		client     map[string]any
+		want       testClientConfig
+		wantIssues []string
+	}{
+		{
+			name:   "valid section",
+			client: map[string]any{"baseUrl": "https://api.example.com", "token": "abc", "timeout": "1m30s", "retries": "3", "scopes": "read, write"},
+			want: testClientConfig{
+				BaseURL: "https://api.example.com",
+				Token:   "abc",
+				Timeout: 90 * time.Second,
+				Retries: 3,
+				Scopes:  []string{"read", "write"},
+			},
+		},
+		{
+			name:   "json list",
+			client: map[string]any{"baseUrl": "https://api.example.com", "token": "abc", "scopes": `["read","write"]`},
+			want:   testClientConfig{BaseURL: "https://api.example.com", Token: "abc", Scopes: []string{"read", "write"}},
+		},
+		{
+			name:       "missing key",
+			client:     map[string]any{"token": "abc"},
+			wantIssues: []string{"key 'client.baseUrl' is missing"},
+		},
+		{
+			name:   "every invalid key is reported",
+			client: map[string]any{"baseUrl": "not a url", "token": "  ", "retries": 9},
+			wantIssues: []string{
+				"key 'client.baseUrl' with value 'not a url' failed constraint 'url'",
+				"key 'client.token' with value '[REDACTED]' failed constraint 'notBlank'",
+				"key 'client.retries' with value '9' failed constraint 'lte=5'",
+			},
+		},
+		{
+			name:       "bare duration",
+			client:     map[string]any{"baseUrl": "https://api.example.com", "token": "abc", "timeout": 20},
+			wantIssues: []string{"got the bare number 20"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := NewAppWithOptions(&AppOptions{Config: map[string]any{"client": test.client}}).Config()
+
+			client, err := BindConfig[testClientConfig](config, "client")
+
+			if len(test.wantIssues) > 0 {
+				var validationError *ConfigValidationError
+				if !errors.As(err, &validationError) {
+					t.Fatalf("expected a config validation error, got %v", err)
+				}
+
+				for _, issue := range test.wantIssues {
+					if !strings.Contains(err.Error(), issue) {
+						t.Errorf("expected the report to contain %q, got:\n%s", issue, err)
+					}
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(client, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, client)
+			}
+		})
+	}
+}
+
+func TestValidateConfigReportsRegisteredBindings(t *testing.T) {
+	app := NewAppWithOptions(&AppOptions{Config: map[string]any{"client": map[string]any{"token": "abc"}}})
+	RegisterConfig[testClientConfig](app, "client")
+
+	err := app.ValidateConfig()
+	if err == nil || !strings.Contains(err.Error(), "key 'client.baseUrl' is missing") {
+		t.Errorf("expected the registered binding to fail validation, got %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type configCommands struct {
+	app              *App
+	registerBindings func() error
+}
+
+func newConfigCommand(app *App, registerBindings func() error) *cobra.Command {
+	commands := &configCommands{
+		app:              app,
+		registerBindings: registerBindings,
+	}
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and validate the merged configuration",
+	}
+
+	printCmd := &cobra.Command{
+		Use:   "print",
+		Short: "Print the merged config for --env with secrets redacted and the source of each key",
+		Args:  cobra.NoArgs,
+		Run:   commands.print,
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the merged config for --env against all registered bindings",
+		Long: "Validate the merged config for --env against all registered bindings, including those registered in " +
+			"ServerOptions.Routes (the Database callback is not run), and exit non-zero on failure",
+		Args: cobra.NoArgs,
+		Run:  commands.validate,
+	}
+
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the keys added, removed and changed between the merged config of --env and --against",
+		Args:  cobra.NoArgs,
+		Run:   commands.diff,
+	}
+	diffCmd.Flags().String("against", "", "env to compare --env against, e.g. prod")
+	_ = diffCmd.MarkFlagRequired("against")
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema or a markdown table of every declared config key",
+		Args:  cobra.NoArgs,
+		Run:   commands.schema,
+	}
+	schemaCmd.Flags().String("format", "json", `output format, "json" for JSON Schema or "markdown"`)
+
+	configCmd.AddCommand(printCmd, validateCmd, diffCmd, schemaCmd)
+
+	return configCmd
+}
+
+func (c *configCommands) fail(cmd *cobra.Command, err error) {
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s\n", err)
+	os.Exit(1)
+}
+
+func (c *configCommands) loadLayers(cmd *cobra.Command, env string) []configLayer {
+	layers, err := loadConfigLayers(c.app.viper.GetString("configDir"), env)
+	if err != nil {
+		c.fail(cmd, fmt.Errorf("error loading config for %s environment: %w", env, err))
+	}
+
+	return layers
+}
+
+// collectBindings runs the server's config registration with gin's debug output on stderr, so it
+// does not end up in the printed schema.
+func (c *configCommands) collectBindings(cmd *cobra.Command) error {
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = cmd.ErrOrStderr()
+	defer func() {
+		gin.DefaultWriter = defaultWriter
+	}()
+
+	return c.registerBindings()
+}
+
+func formatConfigValue(key string, value any) string {
+	if sensitiveKeys.MatchString(key) {
+		return redactedValue
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(encoded)
+}
+
+func changedFlag(cmd *cobra.Command, key string) *pflag.Flag {
+	var changed *pflag.Flag
+
+	cmd.Root().PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed && strings.EqualFold(flag.Name, key) {
+			changed = flag
+		}
+	})
+
+	return changed
+}
+
+func (c *configCommands) keySource(cmd *cobra.Command, layers []configLayer, overrides []configOverride, key string) string {
+	if lo.ContainsBy(overrides, func(override configOverride) bool {
+		return strings.EqualFold(override.key, key)
+	}) {
+		return "--set " + key
+	}
+
+	if flag := changedFlag(cmd, key); flag != nil {
+		return "flag --" + flag.Name
+	}
+
+	envKeys := []string{envKeyFor(c.app.envPrefix, key)}
+	if key == "configdir" {
+		envKeys = append(envKeys, "CONFIG_DIR")
+	}
+
+	for _, envKey := range envKeys {
+		if _, found := os.LookupEnv(envKey); found && envKey != "" {
+			return "env " + envKey
+		}
+	}
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		if layerKeys(layers[i : i+1])[key] {
+			return layers[i].file
+		}
+	}
+
+	return "default"
+}
+
+func (c *configCommands) print(cmd *cobra.Command, _ []string) {
+	env := c.app.viper.GetString("env")
+	c.loadLayers(cmd, env)
+	layers := c.app.configService().layers()
+	config := c.app.Config().GetViper()
+
+	overrides, err := parseConfigOverrides(c.app.configOverrides())
+	if err != nil {
+		c.fail(cmd, err)
+	}
+
+	keys := config.AllKeys()
+	slices.Sort(keys)
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "# merged config for %s environment\n", env)
+	_, _ = fmt.Fprintln(out, "# precedence: default < config files (default, <env>, local, <env>.local) < config sources < env vars < flags < --set")
+
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(out, "%s = %s  # %s\n", key, formatConfigValue(key, config.Get(key)), c.keySource(cmd, layers, overrides, key))
+	}
+}
+
+func (c *configCommands) validate(cmd *cobra.Command, _ []string) {
+	env := c.app.viper.GetString("env")
+	c.loadLayers(cmd, env)
+
+	err := c.collectBindings(cmd)
+	if err == nil {
+		err = c.app.ValidateConfig()
+	}
+
+	if err != nil {
+		c.fail(cmd, fmt.Errorf("config for %s environment is invalid:\n%w", env, err))
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "config for %s environment is valid\n", env)
+}
+
+func mergedLayers(layers []configLayer) *viper.Viper {
+	merged := viper.New()
+	_ = mergeConfigLayers(merged, layers)
+
+	return merged
+}
+
+func writeConfigDiff(out io.Writer, from, to *viper.Viper) int {
+	keys := lo.Uniq(append(from.AllKeys(), to.AllKeys()...))
+	slices.Sort(keys)
+
+	differences := 0
+	for _, key := range keys {
+		fromValue, toValue := from.Get(key), to.Get(key)
+
+		switch {
+		case !from.IsSet(key):
+			_, _ = fmt.Fprintf(out, "+ %s = %s\n", key, formatConfigValue(key, toValue))
+		case !to.IsSet(key):
+			_, _ = fmt.Fprintf(out, "- %s = %s\n", key, formatConfigValue(key, fromValue))
+		case !reflect.DeepEqual(fromValue, toValue):
+			_, _ = fmt.Fprintf(out, "~ %s = %s -> %s\n", key, formatConfigValue(key, fromValue), formatConfigValue(key, toValue))
+		default:
+			continue
+		}
+
+		differences++
+	}
+
+	return differences
+}
+
+func (c *configCommands) diff(cmd *cobra.Command, _ []string) {
+	env := c.app.viper.GetString("env")
+	against, _ := cmd.Flags().GetString("against")
+
+	from := mergedLayers(c.loadLayers(cmd, env))
+	to := mergedLayers(c.loadLayers(cmd, against))
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "--- %s\n+++ %s\n", env, against)
+
+	if writeConfigDiff(out, from, to) == 0 {
+		_, _ = fmt.Fprintln(out, "no differences")
+	}
+}
+
+func (c *configCommands) schema(cmd *cobra.Command, _ []string) {
+	format, _ := cmd.Flags().GetString("format")
+
+	if err := c.collectBindings(cmd); err != nil {
+		c.fail(cmd, fmt.Errorf("config keys of ServerOptions.Routes cannot be collected, config for %s environment is invalid:\n%w",
+			c.app.viper.GetString("env"), err))
+	}
+
+	keys := c.app.ConfigKeys()
+	out := cmd.OutOrStdout()
+
+	switch strings.ToLower(format) {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+
+		if err := encoder.Encode(configJSONSchema(cmd.Root().Name()+" config", keys)); err != nil {
+			c.fail(cmd, err)
+		}
+	case "markdown", "md":
+		writeConfigMarkdown(out, keys)
+	default:
+		c.fail(cmd, fmt.Errorf(`unsupported format "%s", can be "json" or "markdown"`, format))
+	}
+}
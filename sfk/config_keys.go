@@ -0,0 +1,234 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"cmp"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"github.com/samber/lo"
+	"github.com/spf13/viper"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+var frameworkConfigKeys = []types.ConfigKey{
+	{Key: "env", Type: types.ConfigKeyTypeString, Default: "sandbox", Description: "Env of the server, selects the <env> config file"},
+	{Key: "port", Type: types.ConfigKeyTypeInteger, Default: 8083, Description: "Port of the server when listen is not set"},
+	{Key: "configDir", Type: types.ConfigKeyTypeString, Default: defaultConfigDir, Description: "Directory containing the config files, can also be set with CONFIG_DIR"},
+	{Key: "listen", Type: types.ConfigKeyTypeArray, Description: "Listen addresses, e.g. 0.0.0.0:8083, tcp://10.0.0.5:8083 or unix:/run/app.sock?mode=0660"},
+	{Key: "gracefulShutdownSecs", Type: types.ConfigKeyTypeInteger, Default: 1, Description: "Graceful shutdown in seconds, used when gracefulShutdown is not set"},
+	{Key: "gracefulShutdown", Type: types.ConfigKeyTypeDuration, Description: "Graceful shutdown duration, e.g. 30s"},
+	{Key: "rateLimitCallsPerSec", Type: types.ConfigKeyTypeInteger, Description: "Calls per second allowed by the rate limiter, required when it is enabled"},
+	{Key: "maxMemoryLimitInMB", Type: types.ConfigKeyTypeInteger, Description: "Soft memory limit of the Go runtime in MB"},
+	{Key: "requestTimeoutSecs", Type: types.ConfigKeyTypeInteger, Description: "Request timeout in seconds, used when requestTimeout is not set"},
+	{Key: "requestTimeout", Type: types.ConfigKeyTypeDuration, Default: defaultRequestTimeout.String(), Description: "Timeout of a request handled by the timeout middleware, e.g. 20s; 0 uses the default"},
+	{Key: "logLevel", Type: types.ConfigKeyTypeString, Default: "trace", Description: "Minimum level of logged events", Enum: []string{"trace", "debug", "info", "warn", "error", "fatal", "panic", "disabled"}},
+	{Key: "log.format", Type: types.ConfigKeyTypeString, Description: "Format of the log sinks, defaults to json in prod and console elsewhere", Enum: []string{logFormatJSON, logFormatConsole}},
+	{Key: "log.output", Type: types.ConfigKeyTypeString, Default: logOutputStdout, Description: "Destination of the logs when log.sinks is not set: stdout, stderr or a file path"},
+	{Key: "log.sinks", Type: types.ConfigKeyTypeArray, Description: "Simultaneous log sinks, each {output, format} and for files {maxSizeMB, rotateEvery, maxBackups, maxAgeDays, compress, bufferSize}"},
+	{Key: "log.bridge", Type: types.ConfigKeyTypeBoolean, Default: true, Description: "Route log/slog and the standard log package through the framework logger"},
+	{Key: "log.reopenSignal", Type: types.ConfigKeyTypeString, Default: "SIGHUP", Description: "Signal reopening log files after external rotation, before the config reload when it equals config.reloadSignal", Enum: []string{"SIGHUP", "SIGUSR1", "SIGUSR2"}},
+	{Key: logLevelsConfigKey, Type: types.ConfigKeyTypeObject, Description: "Log level overrides by component, e.g. {\"secrets\": \"debug\"}"},
+	{Key: "pprofSecret", Type: types.ConfigKeyTypeString, Description: "Name of the secret authorizing pprof and admin endpoints"},
+	{Key: "clientIds.hashicorp", Type: types.ConfigKeyTypeString, Description: "Client id used to authenticate with Hashicorp Vault Secrets"},
+	{Key: "hashicorp.organizationId", Type: types.ConfigKeyTypeString, Description: "Hashicorp organization id"},
+	{Key: "hashicorp.projectId", Type: types.ConfigKeyTypeString, Description: "Hashicorp project id"},
+	{Key: "secrets.cacheTTL", Type: types.ConfigKeyTypeDuration, Default: defaultSecretsCacheTTL.String(), Description: "How long resolved secrets are cached"},
+	{Key: "secrets.tokenTTL", Type: types.ConfigKeyTypeDuration, Default: defaultSecretsTokenTTL.String(), Description: "How long the secret service access token is cached"},
+	{Key: "tls.certFile", Type: types.ConfigKeyTypeString, Description: "PEM certificate file, enables TLS together with tls.keyFile"},
+	{Key: "tls.keyFile", Type: types.ConfigKeyTypeString, Description: "PEM private key file"},
+	{Key: "tls.certSecret", Type: types.ConfigKeyTypeString, Description: "Name of the secret holding the PEM certificate"},
+	{Key: "tls.keySecret", Type: types.ConfigKeyTypeString, Description: "Name of the secret holding the PEM private key"},
+	{Key: "tls.secretRefresh", Type: types.ConfigKeyTypeDuration, Description: "Interval re-fetching tls.certSecret and tls.keySecret past the secret cache, defaults to secrets.cacheTTL"},
+	{Key: "tls.clientCaFile", Type: types.ConfigKeyTypeString, Description: "PEM CA bundle verifying client certificates, enables mTLS"},
+	{Key: "tls.optionalClientCert", Type: types.ConfigKeyTypeBoolean, Default: false, Description: "Verify client certificates only when presented"},
+	{Key: "tls.minVersion", Type: types.ConfigKeyTypeString, Default: "1.2", Description: "Minimum TLS version", Enum: []string{"1.0", "1.1", "1.2", "1.3"}},
+	{Key: "tls.cipherSuites", Type: types.ConfigKeyTypeArray, Description: "Allowed TLS 1.2 cipher suite names"},
+	{Key: "admin.enabled", Type: types.ConfigKeyTypeBoolean, Default: false, Description: "Serve pprof, health and admin endpoints on a separate listener"},
+	{Key: "admin.host", Type: types.ConfigKeyTypeString, Default: "127.0.0.1", Description: "Host of the admin listener"},
+	{Key: "admin.port", Type: types.ConfigKeyTypeInteger, Default: 8084, Description: "Port of the admin listener"},
+	{Key: "health.public", Type: types.ConfigKeyTypeBoolean, Default: false, Description: "Also serve /livez and /readyz on the main listener when the admin listener is enabled"},
+	{Key: "health.drainDelaySecs", Type: types.ConfigKeyTypeInteger, Default: 0, Description: "Seconds to keep serving after readiness fails on shutdown"},
+	{Key: "lifecycle.startTimeoutSecs", Type: types.ConfigKeyTypeInteger, Default: 30, Description: "Timeout of the start hooks in seconds"},
+	{Key: "lifecycle.stopTimeoutSecs", Type: types.ConfigKeyTypeInteger, Default: 30, Description: "Timeout of the stop hooks in seconds, independent of the graceful shutdown"},
+	{Key: "restart.enabled", Type: types.ConfigKeyTypeBoolean, Default: false, Description: "Enable zero-downtime restart on restart.signal"},
+	{Key: "restart.signal", Type: types.ConfigKeyTypeString, Default: "SIGUSR2", Description: "Signal triggering a zero-downtime restart", Enum: []string{"SIGHUP", "SIGUSR1", "SIGUSR2"}},
+	{Key: "restart.readyTimeoutSecs", Type: types.ConfigKeyTypeInteger, Default: 30, Description: "Seconds to wait for the new process to become ready"},
+	{Key: "server.readHeaderTimeoutSecs", Type: types.ConfigKeyTypeInteger, Default: int(defaultReadHeaderTimeout.Seconds()), Description: "Read header timeout of the HTTP server in seconds, 0 uses the read timeout"},
+	{Key: "server.readTimeoutSecs", Type: types.ConfigKeyTypeInteger, Default: int(defaultReadTimeout.Seconds()), Description: "Read timeout of the HTTP server in seconds, 0 disables it"},
+	{Key: "server.writeTimeoutSecs", Type: types.ConfigKeyTypeInteger, Default: int(defaultWriteTimeout.Seconds()), Description: "Write timeout of the HTTP server in seconds, 0 disables it. Disabled by default when pprof is served by the main server"},
+	{Key: "server.idleTimeoutSecs", Type: types.ConfigKeyTypeInteger, Default: int(defaultIdleTimeout.Seconds()), Description: "Keep-alive idle timeout of the HTTP server in seconds, 0 uses the read timeout"},
+	{Key: "server.maxHeaderBytes", Type: types.ConfigKeyTypeInteger, Default: 1 << 20, Description: "Maximum size of request headers in bytes"},
+	{Key: "server.disableKeepAlives", Type: types.ConfigKeyTypeBoolean, Default: false, Description: "Disable HTTP keep-alives"},
+	{Key: "server.maxConnections", Type: types.ConfigKeyTypeInteger, Default: 0, Description: "Maximum concurrent connections per listener, 0 is unlimited"},
+	{Key: "server.logConnectionState", Type: types.ConfigKeyTypeBoolean, Default: false, Description: "Log connection state changes"},
+	{Key: "http2.h2c", Type: types.ConfigKeyTypeBoolean, Default: false, Description: "Serve HTTP/2 cleartext"},
+	{Key: "http2.maxConcurrentStreams", Type: types.ConfigKeyTypeInteger, Description: "Maximum concurrent HTTP/2 streams per connection"},
+	{Key: "http3.enabled", Type: types.ConfigKeyTypeBoolean, Default: false, Description: "Start an experimental HTTP/3 listener next to the TLS listener"},
+	{Key: "http3.port", Type: types.ConfigKeyTypeInteger, Description: "UDP port of the HTTP/3 listener, defaults to the TLS port"},
+	{Key: "config.watch", Type: types.ConfigKeyTypeBoolean, Default: true, Description: "Reload the config when config files change"},
+	{Key: "config.reloadSignal", Type: types.ConfigKeyTypeString, Default: "SIGHUP", Description: "Signal triggering a config reload", Enum: []string{"SIGHUP", "SIGUSR1", "SIGUSR2"}},
+	{Key: featureFlagsConfigKey, Type: types.ConfigKeyTypeObject, Description: "Feature flags by name, a boolean or {enabled, percentage, variants, default}"},
+}
+
+func configKeyType(t reflect.Type) string {
+	if t == durationType {
+		return types.ConfigKeyTypeDuration
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return types.ConfigKeyTypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return types.ConfigKeyTypeInteger
+	case reflect.Float32, reflect.Float64:
+		return types.ConfigKeyTypeNumber
+	case reflect.Slice, reflect.Array:
+		return types.ConfigKeyTypeArray
+	case reflect.Map, reflect.Struct, reflect.Interface:
+		return types.ConfigKeyTypeObject
+	default:
+		return types.ConfigKeyTypeString
+	}
+}
+
+func joinConfigKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+func structConfigKeys(prefix string, t reflect.Type) []types.ConfigKey {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	keys := make([]types.ConfigKey, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if strings.Contains(options, "squash") {
+			keys = append(keys, structConfigKeys(prefix, fieldType)...)
+			continue
+		}
+
+		key := joinConfigKey(prefix, lo.CoalesceOrEmpty(name, field.Name))
+		if fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeOf(time.Time{}) {
+			keys = append(keys, structConfigKeys(key, fieldType)...)
+			continue
+		}
+
+		configKey := types.ConfigKey{
+			Key:         key,
+			Type:        configKeyType(fieldType),
+			Description: field.Tag.Get("description"),
+		}
+
+		if defaultValue, found := field.Tag.Lookup("default"); found {
+			configKey.Default = parseConfigValue(defaultValue)
+		}
+
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			switch {
+			case rule == "required":
+				configKey.Required = true
+			case strings.HasPrefix(rule, "oneof="):
+				configKey.Enum = strings.Fields(strings.TrimPrefix(rule, "oneof="))
+			}
+		}
+
+		keys = append(keys, configKey)
+	}
+
+	return keys
+}
+
+func mergeConfigKey(existing, next types.ConfigKey) types.ConfigKey {
+	existing.Type = lo.CoalesceOrEmpty(existing.Type, next.Type)
+	existing.Description = lo.CoalesceOrEmpty(existing.Description, next.Description)
+	existing.Required = existing.Required || next.Required
+
+	if existing.Default == nil {
+		existing.Default = next.Default
+	}
+
+	if len(existing.Enum) == 0 {
+		existing.Enum = next.Enum
+	}
+
+	return existing
+}
+
+// DeclareConfigKeys adds keys to the config schema and applies their defaults to the config. RegisterConfig
+// derives them from the mapstructure, validate, default and description tags of a struct. config schema
+// prints a JSON Schema for editors, e.g. "$schema": "./config.schema.json" in config/*.json or VS Code
+// json.schemas, and config schema --format markdown a reference table.
+func (a *App) DeclareConfigKeys(keys ...types.ConfigKey) {
+	a.keysMtx.Lock()
+	a.keys = append(a.keys, keys...)
+	a.keysMtx.Unlock()
+
+	defaults := lo.Filter(keys, func(key types.ConfigKey, _ int) bool {
+		return key.Default != nil
+	})
+
+	if len(defaults) > 0 {
+		a.configureViper(func(v *viper.Viper) {
+			for _, key := range defaults {
+				v.SetDefault(key.Key, key.Default)
+			}
+		})
+	}
+}
+
+func (a *App) ConfigKeys() []types.ConfigKey {
+	a.keysMtx.Lock()
+	declared := append(slices.Clone(frameworkConfigKeys), a.keys...)
+	a.keysMtx.Unlock()
+
+	merged := make(map[string]types.ConfigKey, len(declared))
+	for _, key := range declared {
+		id := strings.ToLower(key.Key)
+		if existing, found := merged[id]; found {
+			merged[id] = mergeConfigKey(existing, key)
+			continue
+		}
+
+		merged[id] = key
+	}
+
+	keys := lo.Values(merged)
+	slices.SortFunc(keys, func(a, b types.ConfigKey) int {
+		return cmp.Compare(strings.ToLower(a.Key), strings.ToLower(b.Key))
+	})
+
+	return keys
+}
+
+func DeclareConfigKeys(keys ...types.ConfigKey) {
+	currentApp().DeclareConfigKeys(keys...)
+}
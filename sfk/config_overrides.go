@@ -0,0 +1,126 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// envNestingSeparator separates the parts of a nested key in an env var name, so a single underscore
+// can stay part of a key.
+const envNestingSeparator = "__"
+
+var envKeyReplacer = strings.NewReplacer(".", envNestingSeparator)
+
+type configOverride struct {
+	key   string
+	value any
+}
+
+func normalizeEnvPrefix(prefix string) string {
+	return strings.ToUpper(strings.TrimSuffix(strings.TrimSpace(prefix), "_"))
+}
+
+// envKeyFor returns the env var that overrides key, or an empty string when no prefix is set and env
+// overrides are disabled.
+func envKeyFor(prefix, key string) string {
+	if prefix == "" {
+		return ""
+	}
+
+	return prefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+func bindPrefixedEnv(v *viper.Viper, prefix string) {
+	if prefix == "" {
+		return
+	}
+
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+
+		rest, found := strings.CutPrefix(name, prefix+"_")
+		if !found || rest == "" {
+			continue
+		}
+
+		key := strings.ToLower(strings.ReplaceAll(rest, envNestingSeparator, "."))
+		_ = v.BindEnv(key, name)
+	}
+}
+
+func parseConfigValue(raw string) any {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return raw
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(trimmed), &value); err != nil {
+		return raw
+	}
+
+	return value
+}
+
+func configStringSlice(value any) []string {
+	raw, ok := value.(string)
+	if !ok {
+		return cast.ToStringSlice(value)
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+		return cast.ToStringSlice(parseConfigValue(raw))
+	}
+
+	if strings.TrimSpace(raw) == "" {
+		return []string{}
+	}
+
+	return lo.Map(strings.Split(raw, ","), func(item string, _ int) string {
+		return strings.TrimSpace(item)
+	})
+}
+
+func parseConfigOverrides(entries []string) ([]configOverride, error) {
+	overrides := make([]configOverride, 0, len(entries))
+
+	for _, entry := range entries {
+		key, value, found := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+
+		if !found || key == "" {
+			return nil, fmt.Errorf(`invalid --set "%s", expected key=value`, entry)
+		}
+
+		overrides = append(overrides, configOverride{key: key, value: parseConfigValue(value)})
+	}
+
+	return overrides, nil
+}
+
+func applyConfigOverrides(v *viper.Viper, overrides []configOverride) {
+	for _, override := range overrides {
+		v.Set(override.key, override.value)
+	}
+}
+
+func structuredValueHook(from reflect.Kind, to reflect.Kind, data any) (any, error) {
+	if from != reflect.String || (to != reflect.Slice && to != reflect.Map) {
+		return data, nil
+	}
+
+	raw := strings.TrimSpace(data.(string))
+	if !strings.HasPrefix(raw, "[") && !strings.HasPrefix(raw, "{") {
+		return data, nil
+	}
+
+	return parseConfigValue(raw), nil
+}
@@ -0,0 +1,101 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"github.com/spf13/viper"
+	"reflect"
+	"testing"
+)
+
+func TestParseConfigOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    []configOverride
+		wantErr bool
+	}{
+		{name: "string", entries: []string{"env=prod"}, want: []configOverride{{key: "env", value: "prod"}}},
+		{name: "number", entries: []string{"rateLimitCallsPerSec=50"}, want: []configOverride{{key: "rateLimitCallsPerSec", value: float64(50)}}},
+		{name: "json list", entries: []string{`listen=[":8083"]`}, want: []configOverride{{key: "listen", value: []any{":8083"}}}},
+		{name: "json map", entries: []string{`labels={"team":"core"}`}, want: []configOverride{{key: "labels", value: map[string]any{"team": "core"}}}},
+		{name: "value with equals", entries: []string{"query=a=b"}, want: []configOverride{{key: "query", value: "a=b"}}},
+		{name: "empty value", entries: []string{" key ="}, want: []configOverride{{key: "key", value: ""}}},
+		{name: "repeated", entries: []string{"a=1", "b=x"}, want: []configOverride{{key: "a", value: float64(1)}, {key: "b", value: "x"}}},
+		{name: "missing equals", entries: []string{"env"}, wantErr: true},
+		{name: "missing key", entries: []string{"=prod"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			overrides, err := parseConfigOverrides(test.entries)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an invalid --set error, got %+v", overrides)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(overrides, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, overrides)
+			}
+		})
+	}
+}
+
+func TestBindPrefixedEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		env    string
+		key    string
+		want   any
+	}{
+		{name: "top level key", prefix: "APP", env: "APP_RATELIMITCALLSPERSEC", key: "rateLimitCallsPerSec", want: "50"},
+		{name: "nested key", prefix: "APP", env: "APP_HASHICORP__ORGANIZATIONID", key: "hashicorp.organizationId", want: "50"},
+		{name: "single underscore stays in the key", prefix: "APP", env: "APP_LOG_LEVEL", key: "log_level", want: "50"},
+		{name: "single underscore does not nest", prefix: "APP", env: "APP_LOG_LEVEL", key: "log.level", want: nil},
+		{name: "other prefix", prefix: "APP", env: "OTHER_PORT", key: "port", want: nil},
+		{name: "unprefixed", prefix: "APP", env: "PORT", key: "port", want: nil},
+		{name: "no prefix disables env overrides", prefix: "", env: "PORT", key: "port", want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(test.env, "50")
+
+			v := viper.New()
+			bindPrefixedEnv(v, test.prefix)
+
+			if got := v.Get(test.key); got != test.want {
+				t.Errorf("expected %s to read %v, got %v", test.key, test.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvKeyFor(t *testing.T) {
+	tests := []struct {
+		prefix string
+		key    string
+		want   string
+	}{
+		{prefix: "APP", key: "port", want: "APP_PORT"},
+		{prefix: "APP", key: "hashicorp.organizationId", want: "APP_HASHICORP__ORGANIZATIONID"},
+		{prefix: "", key: "port", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.prefix+"/"+test.key, func(t *testing.T) {
+			if got := envKeyFor(test.prefix, test.key); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
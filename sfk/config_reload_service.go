@@ -0,0 +1,234 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"context"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const configReloadDebounce = 250 * time.Millisecond
+
+type ConfigReloadService interface {
+	start() error
+	reload()
+	signalName() (string, bool)
+	close()
+}
+
+type configReloadService struct {
+	app      *App
+	config   *configService
+	logger   *zerolog.Logger
+	restart  GracefulRestartService
+	watcher  *fsnotify.Watcher
+	signals  chan os.Signal
+	timer    *time.Timer
+	timerMtx sync.Mutex
+	cancel   context.CancelFunc
+}
+
+func newConfigReloadService(app *App, restart GracefulRestartService) ConfigReloadService {
+	return &configReloadService{
+		app:     app,
+		config:  app.configService(),
+		logger:  app.Logger().ZeroLogger(),
+		restart: restart,
+	}
+}
+
+func (c *configReloadService) reload() {
+	if err := c.config.reload(c.app.validateConfig); err != nil {
+		c.logger.Error().Msgf("Config reload rejected, keeping previous config: %s", err)
+		return
+	}
+
+	c.logWarnings()
+	c.logger.Info().Msg("Config reloaded successfully")
+}
+
+func (c *configReloadService) logWarnings() {
+	for _, warning := range c.config.warnings() {
+		c.logger.Warn().Msg(warning)
+	}
+}
+
+func (c *configReloadService) watchSources() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	for _, source := range c.app.configSources {
+		if logging, ok := source.(loggingConfigSource); ok {
+			logging.useLogger(c.logger)
+		}
+
+		if err := source.Watch(ctx, c.scheduleReload); err != nil {
+			return fmt.Errorf("failed to watch config source %s: %w", source.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func (c *configReloadService) scheduleReload() {
+	c.timerMtx.Lock()
+	defer c.timerMtx.Unlock()
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+
+	c.timer = time.AfterFunc(configReloadDebounce, c.reload)
+}
+
+func (c *configReloadService) isWatchedEvent(event fsnotify.Event, names []string) bool {
+	name := filepath.Base(event.Name)
+	if strings.HasPrefix(name, "..data") {
+		return true
+	}
+
+	extension := filepath.Ext(name)
+
+	return slices.Contains(configFileTypes, strings.TrimPrefix(extension, ".")) &&
+		slices.Contains(names, strings.TrimSuffix(name, extension))
+}
+
+func (c *configReloadService) watch() error {
+	configDir, names := c.config.watchedFiles()
+	watchEnabled := !c.config.GetViper().IsSet("config.watch") || c.config.GetBool("config.watch")
+	if configDir == "" || !watchEnabled {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := watcher.Add(configDir); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	c.watcher = watcher
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if c.isWatchedEvent(event, names) && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename)) {
+					c.scheduleReload()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				c.logger.Error().Err(err).Msg("Config watcher failed")
+			}
+		}
+	}()
+
+	c.logger.Info().Msgf("Watching %s for config changes", configDir)
+
+	return nil
+}
+
+func (c *configReloadService) configuredSignal() (string, os.Signal, bool) {
+	if configDir, _ := c.config.watchedFiles(); configDir == "" {
+		return "", nil, false
+	}
+
+	name := strings.ToUpper(lo.CoalesceOrEmpty(c.config.GetString("config.reloadSignal"), "SIGHUP"))
+	reloadSignal, found := restartSignals[name]
+
+	return name, reloadSignal, found
+}
+
+// signalName returns the signal reloading the config, if reloading on signal is enabled.
+func (c *configReloadService) signalName() (string, bool) {
+	name, reloadSignal, found := c.configuredSignal()
+	if !found {
+		return "", false
+	}
+
+	restartSignal, enabled := c.restart.restartSignal()
+
+	return name, !enabled || restartSignal != reloadSignal
+}
+
+func (c *configReloadService) listenForSignal() {
+	name, reloadSignal, found := c.configuredSignal()
+	if !found {
+		return
+	}
+
+	if _, enabled := c.signalName(); !enabled {
+		c.logger.Warn().Msgf("Config reload signal %s is used for graceful restart, reload on signal disabled", name)
+		return
+	}
+
+	reopenLogs := strings.ToUpper(lo.CoalesceOrEmpty(c.config.GetString("log.reopenSignal"), "SIGHUP")) == name
+
+	c.signals = make(chan os.Signal, 1)
+	signal.Notify(c.signals, reloadSignal)
+
+	go func() {
+		for range c.signals {
+			if reopenLogs && len(c.app.Logger().SinkStats()) > 0 {
+				c.app.loggerService().reopen()
+				c.logger.Info().Msgf("Received %s, log files reopened", name)
+			}
+
+			c.logger.Info().Msgf("Received %s, reloading config...", name)
+			c.reload()
+		}
+	}()
+}
+
+func (c *configReloadService) start() error {
+	c.logWarnings()
+	c.listenForSignal()
+
+	if err := c.watchSources(); err != nil {
+		return err
+	}
+
+	return c.watch()
+}
+
+func (c *configReloadService) close() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+
+	if c.signals != nil {
+		signal.Stop(c.signals)
+		close(c.signals)
+	}
+
+	if c.watcher != nil {
+		_ = c.watcher.Close()
+	}
+
+	c.timerMtx.Lock()
+	defer c.timerMtx.Unlock()
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+}
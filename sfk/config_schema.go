@@ -0,0 +1,127 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"fmt"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"io"
+	"strings"
+)
+
+const (
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+)
+
+var featureFlagSchema = map[string]any{
+	"oneOf": []any{
+		map[string]any{"type": "boolean"},
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"enabled":    map[string]any{"type": "boolean"},
+				"percentage": map[string]any{"type": "number", "minimum": 0, "maximum": 100},
+				"variants":   map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "integer", "minimum": 0}},
+				"default":    map[string]any{"type": "string"},
+			},
+			"additionalProperties": false,
+		},
+	},
+}
+
+func configKeySchema(key types.ConfigKey) map[string]any {
+	schema := make(map[string]any)
+
+	switch key.Type {
+	case types.ConfigKeyTypeDuration:
+		schema["type"] = "string"
+		schema["pattern"] = durationPattern
+	case types.ConfigKeyTypeArray:
+		schema["type"] = "array"
+	case "":
+		schema["type"] = types.ConfigKeyTypeString
+	default:
+		schema["type"] = key.Type
+	}
+
+	if key.Key == featureFlagsConfigKey {
+		schema["additionalProperties"] = featureFlagSchema
+	}
+
+	if key.Description != "" {
+		schema["description"] = key.Description
+	}
+
+	if key.Default != nil {
+		schema["default"] = key.Default
+	}
+
+	if len(key.Enum) > 0 {
+		schema["enum"] = key.Enum
+	}
+
+	return schema
+}
+
+func objectSchema() map[string]any {
+	return map[string]any{
+		"type":       "object",
+		"properties": make(map[string]any),
+	}
+}
+
+func configJSONSchema(title string, keys []types.ConfigKey) map[string]any {
+	root := objectSchema()
+	root["$schema"] = jsonSchemaDraft
+	root["title"] = title
+
+	for _, key := range keys {
+		parts := strings.Split(key.Key, ".")
+		parent := root
+
+		for _, part := range parts[:len(parts)-1] {
+			properties := parent["properties"].(map[string]any)
+
+			child, found := properties[part].(map[string]any)
+			if !found || child["properties"] == nil {
+				child = objectSchema()
+				properties[part] = child
+			}
+
+			parent = child
+		}
+
+		parent["properties"].(map[string]any)[parts[len(parts)-1]] = configKeySchema(key)
+	}
+
+	return root
+}
+
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
+
+func writeConfigMarkdown(out io.Writer, keys []types.ConfigKey) {
+	_, _ = fmt.Fprintln(out, "| Key | Type | Default | Required | Description |")
+	_, _ = fmt.Fprintln(out, "| --- | --- | --- | --- | --- |")
+
+	for _, key := range keys {
+		defaultValue := ""
+		if key.Default != nil {
+			defaultValue = "`" + formatConfigValue("", key.Default) + "`"
+		}
+
+		required := ""
+		if key.Required {
+			required = "yes"
+		}
+
+		description := key.Description
+		if len(key.Enum) > 0 {
+			description = strings.TrimSpace(description + " (one of " + strings.Join(key.Enum, ", ") + ")")
+		}
+
+		_, _ = fmt.Fprintf(out, "| `%s` | %s | %s | %s | %s |\n", key.Key, key.Type, markdownCell(defaultValue), required, markdownCell(description))
+	}
+}
@@ -0,0 +1,142 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	secretReferencePrefix = "secret://"
+	secretFailureTTL      = 30 * time.Second
+)
+
+var secretReferencePattern = regexp.MustCompile(`\$\{secret:([^}]+)\}`)
+
+func isSecretReference(value any) bool {
+	raw, ok := value.(string)
+	if !ok {
+		return false
+	}
+
+	return strings.HasPrefix(raw, secretReferencePrefix) || secretReferencePattern.MatchString(raw)
+}
+
+func hasSecretReferences(value any) bool {
+	switch typed := value.(type) {
+	case map[string]any:
+		for _, nested := range typed {
+			if hasSecretReferences(nested) {
+				return true
+			}
+		}
+	case []any:
+		for _, nested := range typed {
+			if hasSecretReferences(nested) {
+				return true
+			}
+		}
+	default:
+		return isSecretReference(value)
+	}
+
+	return false
+}
+
+// secretFailures remembers secret references that recently failed to resolve, so a broken
+// reference is retried (and logged) at most once per secretFailureTTL. It is shared by the
+// live config service and the candidates it validates on reload.
+type secretFailures struct {
+	mtx   sync.Mutex
+	until map[string]time.Time
+}
+
+func newSecretFailures() *secretFailures {
+	return &secretFailures{until: make(map[string]time.Time)}
+}
+
+func (s *secretFailures) recent(name string) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	until, found := s.until[name]
+
+	return found && time.Now().Before(until)
+}
+
+func (s *secretFailures) record(name string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.until[name] = time.Now().Add(secretFailureTTL)
+}
+
+func (c *configService) recordSecretFailure(name string) {
+	c.secretFailures.record(name)
+
+	if c.logger == nil {
+		return
+	}
+
+	c.logger().ZeroLogger().Error().Str("secret", name).
+		Msgf("Failed to resolve secret reference %s, retrying in %s", name, secretFailureTTL)
+}
+
+func (c *configService) resolveSecret(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if c.secrets == nil {
+		return "", fmt.Errorf("secret '%s' cannot be resolved without a secret service", name)
+	}
+
+	if c.secretFailures.recent(name) {
+		return "", fmt.Errorf("secret '%s' could not be resolved", name)
+	}
+
+	resolver, ok := c.secrets().(SecretResolver)
+	if !ok {
+		return "", fmt.Errorf("secret '%s' cannot be resolved, the secret service does not implement sfk.SecretResolver", name)
+	}
+
+	value, exp := resolver.Resolve(name)
+	if exp != nil {
+		c.recordSecretFailure(name)
+		return "", fmt.Errorf("secret '%s' could not be resolved", name)
+	}
+
+	return value, nil
+}
+
+func (c *configService) resolveSecrets(value any) (any, error) {
+	raw, ok := value.(string)
+	if !ok || !isSecretReference(raw) {
+		return value, nil
+	}
+
+	if name, found := strings.CutPrefix(raw, secretReferencePrefix); found {
+		return c.resolveSecret(name)
+	}
+
+	var errs []error
+	resolved := secretReferencePattern.ReplaceAllStringFunc(raw, func(reference string) string {
+		secret, err := c.resolveSecret(secretReferencePattern.FindStringSubmatch(reference)[1])
+		errs = append(errs, err)
+
+		return secret
+	})
+
+	return resolved, errors.Join(errs...)
+}
+
+func (c *configService) secretReferenceHook(from reflect.Kind, _ reflect.Kind, data any) (any, error) {
+	if from != reflect.String {
+		return data, nil
+	}
+
+	return c.resolveSecrets(data)
+}
@@ -0,0 +1,83 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"bytes"
+	"context"
+	"github.com/omkarsrepo/server-framework/sfk/boom"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"strings"
+	"testing"
+)
+
+type unavailableSecretService struct {
+	calls int
+}
+
+func (u *unavailableSecretService) ValueOf(secretKey string) (string, boom.Exception) {
+	return "", boom.InternalServerError()
+}
+
+func (u *unavailableSecretService) Resolve(secretName string) (string, boom.Exception) {
+	u.calls++
+	return "", boom.InternalServerError()
+}
+
+func (u *unavailableSecretService) Create(secretName string, value ...string) (string, boom.Exception) {
+	return "", boom.InternalServerError()
+}
+
+func (u *unavailableSecretService) PurgeSecretsCache() {}
+
+func (u *unavailableSecretService) Delete(secretName string) boom.Exception {
+	return boom.InternalServerError()
+}
+
+type staticConfigSource struct {
+	settings map[string]any
+}
+
+func (s *staticConfigSource) Name() string {
+	return "static"
+}
+
+func (s *staticConfigSource) Load(ctx context.Context) (map[string]any, error) {
+	return s.settings, nil
+}
+
+func (s *staticConfigSource) Watch(ctx context.Context, onChange func()) error {
+	return nil
+}
+
+func TestReloadCandidateLogsUnresolvedSecretReferences(t *testing.T) {
+	logs := &bytes.Buffer{}
+	secrets := &unavailableSecretService{}
+	app := NewAppWithOptions(&AppOptions{
+		Config:        map[string]any{"env": "test"},
+		SecretService: secrets,
+		LogWriter:     logs,
+		ConfigSources: []types.ConfigSource{&staticConfigSource{settings: map[string]any{"apiToken": "secret://api-token"}}},
+	})
+
+	for attempt := 0; attempt < 2; attempt++ {
+		err := app.configService().reload(func(config ConfigService) error {
+			if value := config.GetString("apiToken"); value != "" {
+				t.Errorf("expected an unresolved secret to read as empty, got %q", value)
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected reload error: %v", err)
+		}
+	}
+
+	if secrets.calls != 1 {
+		t.Errorf("expected the failed secret to be retried once per %s, got %d lookups", secretFailureTTL, secrets.calls)
+	}
+
+	if !strings.Contains(logs.String(), "Failed to resolve secret reference api-token") {
+		t.Errorf("expected the reload failure to be logged, got %q", logs.String())
+	}
+}
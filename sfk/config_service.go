@@ -3,102 +3,487 @@
 package sfk
 
 import (
+	"errors"
 	"fmt"
+	"github.com/mitchellh/mapstructure"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
-	configServiceInstance *configService
-	configServiceOnce     sync.Once
-	configFiles           = map[string]string{
-		"default": "../config/default.json",
-		"sandbox": "../config/sandbox.json",
-		"prod":    "../config/prod.json",
-	}
-	configFileType = "json"
+	configFileTypes = []string{"json", "yaml", "yml", "toml"}
+	sensitiveKeys   = regexp.MustCompile(`(?i)(secret|password|token|credential|apikey|privatekey)`)
 )
 
+const (
+	redactedValue    = "[REDACTED]"
+	defaultConfigDir = "../config"
+)
+
+type configSnapshot struct {
+	viper    *viper.Viper
+	layers   []configLayer
+	warnings []string
+}
+
+type configSubscriber struct {
+	key     string
+	handler func(oldValue, newValue any)
+}
+
 type configService struct {
-	viper *viper.Viper
+	base           *viper.Viper
+	setup          func() []func(v *viper.Viper)
+	settings       map[string]any
+	secrets        func() SecretService
+	logger         func() LoggerService
+	secretFailures *secretFailures
+	overrides      []configOverride
+	sources        []types.ConfigSource
+	snapshot       atomic.Pointer[configSnapshot]
+	reloadMtx      sync.Mutex
+	subscribersMtx sync.Mutex
+	subscribers    []configSubscriber
 }
 
+// ConfigService reads the current config snapshot. Values written as secret://<name> or containing
+// ${secret:<name>} are resolved through the SecretResolver by the getters and Bind, cached with the secret
+// cache TTL, while GetViper, /admin/config and validation reports only show the reference. A reference that
+// cannot be resolved fails Bind and startup validation, is logged by name, reads as an empty string and is
+// retried after 30 seconds.
 type ConfigService interface {
 	GetString(key string) string
 	GetInt(key string) int
 	GetInt64(key string) int64
 	GetBool(key string) bool
+	GetFloat64(key string) float64
+	// GetDuration reads a duration string such as "1m30s". Bare non-zero numbers are invalid rather than
+	// nanoseconds and read as 0, or as the fallback of GetDurationOrDefault.
+	GetDuration(key string) time.Duration
+	// GetStringSlice reads a list, a JSON array or a comma separated string.
+	GetStringSlice(key string) []string
+	GetStringMap(key string) map[string]any
+	GetStringOrDefault(key string, defaultValue string) string
+	GetIntOrDefault(key string, defaultValue int) int
+	GetInt64OrDefault(key string, defaultValue int64) int64
+	GetBoolOrDefault(key string, defaultValue bool) bool
+	GetFloat64OrDefault(key string, defaultValue float64) float64
+	GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration
+	GetStringSliceOrDefault(key string, defaultValue []string) []string
+	IsSet(key string) bool
+	// Sub returns a live view of the section at prefix that follows reloads.
+	Sub(prefix string) ConfigService
+	// GetViper returns the current config snapshot. The global viper instance only holds the bootstrap
+	// flags and env and does not see reloads.
 	GetViper() *viper.Viper
+	// OnChange subscribes to reloads, triggered when a config file changes (unless config.watch is false)
+	// or on config.reloadSignal. A reload is validated against every registered binding and swapped in
+	// atomically, invalid config keeps the previous one. rateLimitCallsPerSec, requestTimeout and logLevel
+	// apply without a restart.
+	OnChange(key string, handler func(oldValue, newValue any))
 }
 
-func buildConfig(isDefault bool) *viper.Viper {
-	config := viper.New()
-	config.SetConfigType(configFileType)
-	env := "default"
+type configLayer struct {
+	name     string
+	file     string
+	settings map[string]any
+}
 
-	if isDefault {
-		env = "default"
-	} else {
-		env = viper.GetString("env")
-	}
+func findConfigFile(configDir, name string) (string, []string) {
+	searched := make([]string, 0, len(configFileTypes))
+
+	for _, fileType := range configFileTypes {
+		configFilePath := filepath.Join(configDir, name+"."+fileType)
+		searched = append(searched, configFilePath)
 
-	configFilePath, found := configFiles[env]
-	if !found {
-		panic(fmt.Sprintf(`Config file for %s env not found`, env))
+		if info, err := os.Stat(configFilePath); err == nil && !info.IsDir() {
+			return configFilePath, searched
+		}
 	}
 
+	return "", searched
+}
+
+func readConfigFile(configFilePath string) (map[string]any, error) {
+	config := viper.New()
 	config.SetConfigFile(configFilePath)
+	config.SetConfigType(strings.TrimPrefix(filepath.Ext(configFilePath), "."))
 
 	if err := config.ReadInConfig(); err != nil {
-		panic(fmt.Sprintf(`Error reading config file for %s environment. Error %s`, env, err))
+		return nil, err
+	}
+
+	return config.AllSettings(), nil
+}
+
+func loadConfigLayer(configDir, name string, required bool) (*configLayer, error) {
+	configFilePath, searched := findConfigFile(configDir, name)
+	if configFilePath == "" {
+		if required {
+			return nil, fmt.Errorf(`config file for "%s" not found, searched: %s`, name, strings.Join(searched, ", "))
+		}
+
+		return nil, nil
 	}
 
-	return config
+	settings, err := readConfigFile(configFilePath)
+	if err != nil {
+		return nil, fmt.Errorf(`error reading config file %s: %w`, configFilePath, err)
+	}
+
+	return &configLayer{name: name, file: configFilePath, settings: settings}, nil
+}
+
+// loadConfigLayers reads default.* and <env>.* from configDir, in json, yaml, yml or toml, followed by the
+// optional local.* and <env>.local.* overrides that are meant to stay out of git. A missing required file
+// fails with every path searched.
+func loadConfigLayers(configDir, env string) ([]configLayer, error) {
+	if configDir == "" {
+		configDir = defaultConfigDir
+	}
+
+	files := []struct {
+		name     string
+		required bool
+	}{
+		{name: "default", required: true},
+		{name: env, required: true},
+		{name: "local", required: false},
+		{name: env + ".local", required: false},
+	}
+
+	layers := make([]configLayer, 0, len(files))
+	for _, file := range files {
+		layer, err := loadConfigLayer(configDir, file.name, file.required)
+		if err != nil {
+			return nil, err
+		}
+
+		if layer != nil {
+			layers = append(layers, *layer)
+		}
+	}
+
+	return layers, nil
+}
+
+func mergeConfigLayers(v *viper.Viper, layers []configLayer) error {
+	for _, layer := range layers {
+		if err := v.MergeConfigMap(layer.settings); err != nil {
+			return fmt.Errorf(`error merging config file %s: %w`, layer.file, err)
+		}
+	}
+
+	return nil
 }
 
-func getViper() *viper.Viper {
-	defaultConfig := buildConfig(true)
-	envConfig := buildConfig(false)
+func (c *configService) loadLayers() ([]configLayer, []string, error) {
+	layers := []configLayer{{name: "memory", file: "in-memory", settings: c.settings}}
+
+	if c.settings == nil {
+		fileLayers, err := loadConfigLayers(c.base.GetString("configDir"), c.base.GetString("env"))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		layers = fileLayers
+	}
+
+	sourceLayers, warnings, err := loadSourceLayers(c.sources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(layers, sourceLayers...), warnings, nil
+}
 
-	err := viper.MergeConfigMap(defaultConfig.AllSettings())
+func (c *configService) loadConfig() *configSnapshot {
+	layers, warnings, err := c.loadLayers()
 	if err != nil {
+		panic(fmt.Sprintf(`Error loading config for %s environment. %s`, c.base.GetString("env"), err))
+	}
+
+	if err := mergeConfigLayers(c.base, layers); err != nil {
 		panic(err)
 	}
 
-	err = viper.MergeConfigMap(envConfig.AllSettings())
+	applyConfigOverrides(c.base, c.overrides)
+
+	return &configSnapshot{viper: c.base, layers: layers, warnings: warnings}
+}
+
+func newConfigService(app *App) *configService {
+	overrides, err := parseConfigOverrides(app.configOverrides())
 	if err != nil {
 		panic(err)
 	}
 
-	return viper.GetViper()
+	service := &configService{
+		base:           app.viper,
+		setup:          app.viperSetups,
+		settings:       app.options.Config,
+		secrets:        app.Secrets,
+		logger:         app.Logger,
+		secretFailures: newSecretFailures(),
+		overrides:      overrides,
+		sources:        app.configSources,
+	}
+
+	service.snapshot.Store(service.loadConfig())
+
+	return service
 }
 
 func ConfigServiceInstance() ConfigService {
-	configServiceOnce.Do(func() {
-		configServiceInstance = &configService{
-			viper: getViper(),
-		}
-	})
+	return currentApp().Config()
+}
+
+func (c *configService) viper() *viper.Viper {
+	return c.snapshot.Load().viper
+}
+
+func (c *configService) get(key string) any {
+	value, _ := c.resolveSecrets(c.viper().Get(key))
 
-	return configServiceInstance
+	return value
 }
 
 func (c *configService) GetString(key string) string {
-	return c.viper.GetString(key)
+	return cast.ToString(c.get(key))
 }
 
 func (c *configService) GetInt(key string) int {
-	return c.viper.GetInt(key)
+	return cast.ToInt(c.get(key))
 }
 
 func (c *configService) GetInt64(key string) int64 {
-	return c.viper.GetInt64(key)
+	return cast.ToInt64(c.get(key))
 }
 
 func (c *configService) GetBool(key string) bool {
-	return c.viper.GetBool(key)
+	return cast.ToBool(c.get(key))
+}
+
+func (c *configService) GetFloat64(key string) float64 {
+	return cast.ToFloat64(c.get(key))
+}
+
+func (c *configService) GetDuration(key string) time.Duration {
+	duration, _ := configDuration(c.get(key))
+
+	return duration
+}
+
+func (c *configService) GetStringSlice(key string) []string {
+	return configStringSlice(c.get(key))
+}
+
+func (c *configService) GetStringMap(key string) map[string]any {
+	value := configSection(c.viper().AllSettings(), key)
+
+	if raw, ok := value.(string); ok {
+		resolved, _ := c.resolveSecrets(raw)
+		return cast.ToStringMap(parseConfigValue(resolved.(string)))
+	}
+
+	return cast.ToStringMap(value)
+}
+
+func (c *configService) GetStringOrDefault(key string, defaultValue string) string {
+	return lo.Ternary(c.IsSet(key), c.GetString(key), defaultValue)
+}
+
+func (c *configService) GetIntOrDefault(key string, defaultValue int) int {
+	return lo.Ternary(c.IsSet(key), c.GetInt(key), defaultValue)
+}
+
+func (c *configService) GetInt64OrDefault(key string, defaultValue int64) int64 {
+	return lo.Ternary(c.IsSet(key), c.GetInt64(key), defaultValue)
+}
+
+func (c *configService) GetBoolOrDefault(key string, defaultValue bool) bool {
+	return lo.Ternary(c.IsSet(key), c.GetBool(key), defaultValue)
+}
+
+func (c *configService) GetFloat64OrDefault(key string, defaultValue float64) float64 {
+	return lo.Ternary(c.IsSet(key), c.GetFloat64(key), defaultValue)
+}
+
+func (c *configService) GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if duration, valid := configDuration(c.get(key)); c.IsSet(key) && valid {
+		return duration
+	}
+
+	return defaultValue
+}
+
+func (c *configService) GetStringSliceOrDefault(key string, defaultValue []string) []string {
+	return lo.Ternary(c.IsSet(key), c.GetStringSlice(key), defaultValue)
+}
+
+func (c *configService) IsSet(key string) bool {
+	return c.viper().IsSet(key)
+}
+
+func (c *configService) Sub(prefix string) ConfigService {
+	return newScopedConfigService(c, prefix)
 }
 
 func (c *configService) GetViper() *viper.Viper {
-	return c.viper
+	return c.viper()
+}
+
+func (c *configService) decodeHook(resolveSecrets bool) mapstructure.DecodeHookFunc {
+	if !resolveSecrets {
+		return configDecodeHook()
+	}
+
+	return mapstructure.ComposeDecodeHookFunc(mapstructure.DecodeHookFuncKind(c.secretReferenceHook), configDecodeHook())
+}
+
+func (c *configService) OnChange(key string, handler func(oldValue, newValue any)) {
+	c.subscribersMtx.Lock()
+	defer c.subscribersMtx.Unlock()
+
+	c.subscribers = append(c.subscribers, configSubscriber{key: key, handler: handler})
+}
+
+func (c *configService) watchedFiles() (string, []string) {
+	if c.settings != nil {
+		return "", nil
+	}
+
+	configDir := c.base.GetString("configDir")
+	if configDir == "" {
+		configDir = defaultConfigDir
+	}
+
+	env := c.base.GetString("env")
+
+	return configDir, []string{"default", env, "local", env + ".local"}
+}
+
+func (c *configService) warnings() []string {
+	return c.snapshot.Load().warnings
+}
+
+func (c *configService) layers() []configLayer {
+	return c.snapshot.Load().layers
+}
+
+func layerKeys(layers []configLayer) map[string]bool {
+	merged := viper.New()
+	_ = mergeConfigLayers(merged, layers)
+
+	return lo.SliceToMap(merged.AllKeys(), func(key string) (string, bool) {
+		return key, true
+	})
+}
+
+func (c *configService) buildSnapshot() (*configSnapshot, error) {
+	current := c.snapshot.Load()
+
+	layers, warnings, err := c.loadLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	next := viper.New()
+	lo.ForEach(c.setup(), func(setup func(v *viper.Viper), _ int) {
+		setup(next)
+	})
+
+	if err := mergeConfigLayers(next, layers); err != nil {
+		return nil, err
+	}
+
+	applyConfigOverrides(next, c.overrides)
+
+	fileKeys := layerKeys(current.layers)
+	for _, key := range current.viper.AllKeys() {
+		if !fileKeys[key] && !next.IsSet(key) {
+			next.SetDefault(key, current.viper.Get(key))
+		}
+	}
+
+	return &configSnapshot{viper: next, layers: layers, warnings: warnings}, nil
+}
+
+func (c *configService) reload(validate func(config ConfigService) error) error {
+	if c.settings != nil && len(c.sources) == 0 {
+		return errors.New("config was provided in memory and cannot be reloaded")
+	}
+
+	c.reloadMtx.Lock()
+	defer c.reloadMtx.Unlock()
+
+	next, err := c.buildSnapshot()
+	if err != nil {
+		return err
+	}
+
+	candidate := &configService{
+		base:           c.base,
+		settings:       c.settings,
+		secrets:        c.secrets,
+		logger:         c.logger,
+		secretFailures: c.secretFailures,
+	}
+	candidate.snapshot.Store(next)
+
+	if validate != nil {
+		if err := validate(candidate); err != nil {
+			return err
+		}
+	}
+
+	previous := c.snapshot.Swap(next)
+	c.notify(previous.viper, next.viper)
+
+	return nil
+}
+
+func (c *configService) notify(previous, next *viper.Viper) {
+	c.subscribersMtx.Lock()
+	subscribers := append([]configSubscriber(nil), c.subscribers...)
+	c.subscribersMtx.Unlock()
+
+	for _, subscriber := range subscribers {
+		oldValue := previous.Get(subscriber.key)
+		newValue := next.Get(subscriber.key)
+
+		if !reflect.DeepEqual(oldValue, newValue) {
+			subscriber.handler(oldValue, newValue)
+		}
+	}
+}
+
+func redactSettings(settings map[string]any) map[string]any {
+	redacted := make(map[string]any, len(settings))
+
+	for key, value := range settings {
+		if sensitiveKeys.MatchString(key) {
+			redacted[key] = redactedValue
+			continue
+		}
+
+		if nested, ok := value.(map[string]any); ok {
+			redacted[key] = redactSettings(nested)
+			continue
+		}
+
+		redacted[key] = value
+	}
+
+	return redacted
 }
@@ -0,0 +1,62 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"testing"
+	"time"
+)
+
+func TestConfigServiceGetDuration(t *testing.T) {
+	config := NewAppWithOptions(&AppOptions{Config: map[string]any{
+		"duration": "20s",
+		"bareZero": 0,
+		"bare":     20,
+		"invalid":  "soon",
+	}}).Config()
+
+	tests := []struct {
+		key         string
+		want        time.Duration
+		wantDefault time.Duration
+	}{
+		{key: "duration", want: 20 * time.Second, wantDefault: 20 * time.Second},
+		{key: "bareZero", want: 0, wantDefault: 0},
+		{key: "bare", want: 0, wantDefault: time.Minute},
+		{key: "invalid", want: 0, wantDefault: time.Minute},
+		{key: "missing", want: 0, wantDefault: time.Minute},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			if got := config.GetDuration(test.key); got != test.want {
+				t.Errorf("expected GetDuration to return %s, got %s", test.want, got)
+			}
+
+			if got := config.GetDurationOrDefault(test.key, time.Minute); got != test.wantDefault {
+				t.Errorf("expected GetDurationOrDefault to return %s, got %s", test.wantDefault, got)
+			}
+		})
+	}
+}
+
+func TestReloadAppliesConfigKeysDeclaredAfterLoad(t *testing.T) {
+	source := &staticConfigSource{settings: map[string]any{"farewell": "see you"}}
+	app := NewAppWithOptions(&AppOptions{
+		Config:        map[string]any{"env": "test"},
+		ConfigSources: []types.ConfigSource{source},
+	})
+	app.Config()
+
+	app.DeclareConfigKeys(types.ConfigKey{Key: "farewell", Type: types.ConfigKeyTypeString, Default: "bye"})
+	source.settings = map[string]any{}
+
+	if err := app.configService().reload(nil); err != nil {
+		t.Fatalf("unexpected reload error: %v", err)
+	}
+
+	if farewell := app.Config().GetString("farewell"); farewell != "bye" {
+		t.Errorf("expected the declared default once the key is removed from the source, got %q", farewell)
+	}
+}
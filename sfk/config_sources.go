@@ -0,0 +1,345 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-resty/resty/v2"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultConfigSourceTimeout   = 10 * time.Second
+	configSourcesLoadTimeout     = 30 * time.Second
+	configSourceErrorLogInterval = 5 * time.Minute
+)
+
+var ErrConfigSourceStale = errors.New("config source unavailable, using last known good config")
+
+type loggingConfigSource interface {
+	useLogger(logger *zerolog.Logger)
+}
+
+type polledConfig struct {
+	body     []byte
+	settings map[string]any
+}
+
+type httpConfigSource struct {
+	options        *types.HTTPConfigSourceOptions
+	client         *resty.Client
+	lastHash       [sha256.Size]byte
+	polled         *polledConfig
+	hashMtx        sync.Mutex
+	logger         *zerolog.Logger
+	failures       int
+	lastFailureLog time.Time
+}
+
+func NewHTTPConfigSource(options *types.HTTPConfigSourceOptions) types.ConfigSource {
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = defaultConfigSourceTimeout
+	}
+
+	return &httpConfigSource{
+		options: options,
+		client:  resty.New().SetTimeout(timeout).SetHeaders(options.Headers),
+	}
+}
+
+func (h *httpConfigSource) useLogger(logger *zerolog.Logger) {
+	h.logger = logger
+}
+
+func (h *httpConfigSource) pollFailed(err error) {
+	h.failures++
+	if h.logger == nil || (h.failures > 1 && time.Since(h.lastFailureLog) < configSourceErrorLogInterval) {
+		return
+	}
+
+	h.lastFailureLog = time.Now()
+	h.logger.Error().Err(err).Int("failures", h.failures).
+		Msgf("Failed to poll config source %s, keeping the current config", h.Name())
+}
+
+func (h *httpConfigSource) pollSucceeded() {
+	if h.failures > 0 && h.logger != nil {
+		h.logger.Info().Int("failures", h.failures).Msgf("Config source %s is reachable again", h.Name())
+	}
+
+	h.failures = 0
+}
+
+func (h *httpConfigSource) Name() string {
+	parsed, err := url.Parse(h.options.URL)
+	if err != nil {
+		return "remote"
+	}
+
+	return parsed.Scheme + "://" + parsed.Host + parsed.Path
+}
+
+func (h *httpConfigSource) fetch(ctx context.Context) ([]byte, map[string]any, error) {
+	resp, err := h.client.R().SetContext(ctx).SetHeader("Accept", "application/json").Get(h.options.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, nil, fmt.Errorf("config source %s responded with status %d", h.Name(), resp.StatusCode())
+	}
+
+	settings, err := decodeConfigSettings(resp.Body())
+	if err != nil {
+		return nil, nil, fmt.Errorf("config source %s returned invalid config: %w", h.Name(), err)
+	}
+
+	return resp.Body(), settings, nil
+}
+
+func decodeConfigSettings(body []byte) (map[string]any, error) {
+	settings := make(map[string]any)
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err := decoder.Decode(&settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (h *httpConfigSource) changed(body []byte) bool {
+	h.hashMtx.Lock()
+	defer h.hashMtx.Unlock()
+
+	hash := sha256.Sum256(body)
+	if hash == h.lastHash {
+		return false
+	}
+
+	h.lastHash = hash
+
+	return true
+}
+
+// keepPolled holds a changed payload fetched by the poller for the reload it triggers, so a poll
+// fetches the config only once.
+func (h *httpConfigSource) keepPolled(body []byte, settings map[string]any) {
+	h.hashMtx.Lock()
+	defer h.hashMtx.Unlock()
+
+	h.polled = &polledConfig{body: body, settings: settings}
+}
+
+func (h *httpConfigSource) polledOrFetch(ctx context.Context) ([]byte, map[string]any, error) {
+	h.hashMtx.Lock()
+	polled := h.polled
+	h.polled = nil
+	h.hashMtx.Unlock()
+
+	if polled != nil {
+		return polled.body, polled.settings, nil
+	}
+
+	return h.fetch(ctx)
+}
+
+func (h *httpConfigSource) writeCache(body []byte) error {
+	if h.options.CacheFile == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.options.CacheFile), 0700); err != nil {
+		return err
+	}
+
+	tmpFile := h.options.CacheFile + ".tmp"
+	if err := os.WriteFile(tmpFile, body, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, h.options.CacheFile)
+}
+
+func (h *httpConfigSource) readCache() (map[string]any, error) {
+	if h.options.CacheFile == "" {
+		return nil, errors.New("no cache file configured")
+	}
+
+	body, err := os.ReadFile(h.options.CacheFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeConfigSettings(body)
+}
+
+func (h *httpConfigSource) Load(ctx context.Context) (map[string]any, error) {
+	body, settings, err := h.polledOrFetch(ctx)
+	if err != nil {
+		cached, cacheErr := h.readCache()
+		if cacheErr != nil {
+			return nil, err
+		}
+
+		return cached, fmt.Errorf("%w (%s): %s", ErrConfigSourceStale, h.options.CacheFile, err)
+	}
+
+	h.changed(body)
+
+	if err := h.writeCache(body); err != nil {
+		return settings, fmt.Errorf("failed to cache config from %s: %w", h.Name(), err)
+	}
+
+	return settings, nil
+}
+
+func (h *httpConfigSource) Watch(ctx context.Context, onChange func()) error {
+	if h.options.PollInterval <= 0 {
+		return nil
+	}
+
+	go func() {
+		ticker := time.NewTicker(h.options.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				body, settings, err := h.fetch(ctx)
+				if err != nil {
+					h.pollFailed(err)
+					continue
+				}
+
+				h.pollSucceeded()
+				if h.changed(body) {
+					h.keepPolled(body, settings)
+					onChange()
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+type directoryConfigSource struct {
+	dir string
+}
+
+// NewDirectoryConfigSource merges every config file in dir and reloads when they change, handy in tests.
+func NewDirectoryConfigSource(dir string) types.ConfigSource {
+	return &directoryConfigSource{dir: dir}
+}
+
+func (d *directoryConfigSource) Name() string {
+	return d.dir
+}
+
+func isConfigFile(name string) bool {
+	return slices.Contains(configFileTypes, strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+func (d *directoryConfigSource) Load(context.Context) (map[string]any, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := viper.New()
+	for _, entry := range entries {
+		if entry.IsDir() || !isConfigFile(entry.Name()) {
+			continue
+		}
+
+		settings, err := readConfigFile(filepath.Join(d.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf(`error reading config file %s: %w`, filepath.Join(d.dir, entry.Name()), err)
+		}
+
+		if err := merged.MergeConfigMap(settings); err != nil {
+			return nil, err
+		}
+	}
+
+	return merged.AllSettings(), nil
+}
+
+func (d *directoryConfigSource) Watch(ctx context.Context, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := watcher.Add(d.dir); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if isConfigFile(event.Name) || strings.HasPrefix(filepath.Base(event.Name), "..data") {
+					onChange()
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func loadSourceLayers(sources []types.ConfigSource) ([]configLayer, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), configSourcesLoadTimeout)
+	defer cancel()
+
+	layers := make([]configLayer, 0, len(sources))
+	warnings := make([]string, 0)
+
+	for _, source := range sources {
+		settings, err := source.Load(ctx)
+		if err != nil && settings == nil {
+			return nil, nil, fmt.Errorf(`error loading config source %s: %w`, source.Name(), err)
+		}
+
+		if err != nil {
+			warnings = append(warnings, err.Error())
+		}
+
+		layers = append(layers, configLayer{name: "source", file: source.Name(), settings: settings})
+	}
+
+	return layers, warnings, nil
+}
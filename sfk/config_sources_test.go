@@ -0,0 +1,60 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"context"
+	"fmt"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPConfigSourceReloadUsesPolledPayload(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := requests.Add(1)
+		_, _ = fmt.Fprintf(w, `{"version": %d}`, count)
+	}))
+	defer server.Close()
+
+	source := NewHTTPConfigSource(&types.HTTPConfigSourceOptions{URL: server.URL, PollInterval: 10 * time.Millisecond})
+
+	if _, err := source.Load(context.Background()); err != nil {
+		t.Fatalf("failed to load the config source: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	loaded := make(chan map[string]any, 1)
+	err := source.Watch(ctx, func() {
+		cancel()
+
+		settings, err := source.Load(context.Background())
+		if err != nil {
+			t.Errorf("failed to reload the config source: %v", err)
+		}
+
+		if count := requests.Load(); count != 2 {
+			t.Errorf("expected one request for the load and one for the poll, got %d", count)
+		}
+
+		loaded <- settings
+	})
+	if err != nil {
+		t.Fatalf("failed to watch the config source: %v", err)
+	}
+
+	select {
+	case settings := <-loaded:
+		if fmt.Sprint(settings["version"]) != "2" {
+			t.Errorf("expected the reload to use the polled version 2, got %v", settings["version"])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the poll to trigger a reload")
+	}
+}
@@ -0,0 +1,87 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type requestLoggerContextKey struct{}
+
+func WithLogger(ctx context.Context, logger *zerolog.Logger) context.Context {
+	return context.WithValue(ctx, requestLoggerContextKey{}, logger)
+}
+
+func requestContext(ctx context.Context) context.Context {
+	if ginCtx, ok := ctx.(*gin.Context); ok {
+		if ginCtx == nil || ginCtx.Request == nil {
+			return nil
+		}
+
+		return ginCtx.Request.Context()
+	}
+
+	return ctx
+}
+
+func loggerFromContext(ctx context.Context) (*zerolog.Logger, bool) {
+	ctx = requestContext(ctx)
+	if ctx == nil {
+		return nil, false
+	}
+
+	logger, ok := ctx.Value(requestLoggerContextKey{}).(*zerolog.Logger)
+
+	return logger, ok && logger != nil
+}
+
+// Log returns the request logger the trace middleware stored in ctx, carrying traceId, route, method and
+// clientIp, or the default logger outside a request. The LoggerService gin methods and Ctx use the same
+// logger, and the request timeout derives from the request context so handlers see its values.
+func Log(ctx context.Context) *zerolog.Logger {
+	if logger, ok := loggerFromContext(ctx); ok {
+		return logger
+	}
+
+	return currentApp().Logger().ZeroLogger()
+}
+
+func newRequestLogger(ginCtx *gin.Context, logger *zerolog.Logger, traceId string) *zerolog.Logger {
+	requestLogger := logger.With().
+		Str("traceId", traceId).
+		Str("route", ginCtx.FullPath()).
+		Str("method", ginCtx.Request.Method).
+		Str("clientIp", ginCtx.ClientIP()).
+		Logger()
+
+	return &requestLogger
+}
+
+func updateRequestLogger(ginCtx *gin.Context, update func(logger zerolog.Context) zerolog.Context) {
+	logger, ok := loggerFromContext(ginCtx)
+	if !ok {
+		return
+	}
+
+	updated := update(logger.With()).Logger()
+	ginCtx.Request = ginCtx.Request.WithContext(WithLogger(ginCtx.Request.Context(), &updated))
+}
+
+// SetLogUserId adds userId to the request logger of ginCtx.
+func SetLogUserId(ginCtx *gin.Context, userId string) {
+	updateRequestLogger(ginCtx, func(logger zerolog.Context) zerolog.Context {
+		return logger.Str("userId", userId)
+	})
+}
+
+func ApplyLogUserId(userIdFunc func(ginCtx *gin.Context) string) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if userId := userIdFunc(ginCtx); userId != "" {
+			SetLogUserId(ginCtx, userId)
+		}
+
+		ginCtx.Next()
+	}
+}
@@ -0,0 +1,268 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/mitchellh/mapstructure"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	featureFlagsConfigKey     = "featureFlags"
+	featureFlagKeyContextKey  = "FEATURE_FLAG_KEY"
+	featureFlagTypeBoolean    = "boolean"
+	featureFlagTypePercentage = "percentage"
+	featureFlagTypeVariant    = "variant"
+	featureFlagBuckets        = 10000
+)
+
+type FeatureFlag struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Enabled    bool           `json:"enabled"`
+	Percentage float64        `json:"percentage,omitempty"`
+	Variants   map[string]int `json:"variants,omitempty"`
+	Default    string         `json:"default,omitempty"`
+}
+
+type FeatureFlagEvaluation struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Variant string `json:"variant,omitempty"`
+}
+
+// FeatureFlags evaluates the flags under featureFlags: booleans (newCheckout: true), percentage rollouts
+// ({percentage: 25}) or weighted variants ({variants: {control: 50, blue: 50}, default: control}), each
+// optionally {enabled: false}. Flags are validated at startup and reloaded with the config. The key is
+// hashed with the flag name, so a key always gets the same result for a flag. The admin listener lists
+// them under GET /admin/featureFlags[?key=...].
+type FeatureFlags interface {
+	IsEnabled(name, key string) bool
+	Variant(name, key string) string
+	Evaluate(name, key string) FeatureFlagEvaluation
+	Flags() []FeatureFlag
+}
+
+type featureFlagConfig struct {
+	Enabled    *bool          `mapstructure:"enabled"`
+	Percentage *float64       `mapstructure:"percentage"`
+	Variants   map[string]int `mapstructure:"variants"`
+	Default    string         `mapstructure:"default"`
+}
+
+type featureFlags struct {
+	flags  atomic.Pointer[map[string]FeatureFlag]
+	logger *zerolog.Logger
+}
+
+func newFeatureFlags(config ConfigService, logger LoggerService) FeatureFlags {
+	service := &featureFlags{
+		logger: logger.ZeroLogger(),
+	}
+
+	flags, err := parseFeatureFlags(config)
+	if err != nil {
+		panic(err)
+	}
+	service.flags.Store(&flags)
+
+	config.OnChange(featureFlagsConfigKey, func(_, _ any) {
+		flags, err := parseFeatureFlags(config)
+		if err != nil {
+			service.logger.Error().Msgf("Feature flags not updated: %s", err)
+			return
+		}
+
+		service.flags.Store(&flags)
+		service.logger.Info().Msgf("Feature flags updated, %d flags defined", len(flags))
+	})
+
+	return service
+}
+
+func FeatureFlagsInstance() FeatureFlags {
+	return currentApp().FeatureFlags()
+}
+
+func parseFeatureFlag(name string, value any) (FeatureFlag, error) {
+	if enabled, ok := value.(bool); ok {
+		return FeatureFlag{Name: name, Type: featureFlagTypeBoolean, Enabled: enabled}, nil
+	}
+
+	var flagConfig featureFlagConfig
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           &flagConfig,
+		WeaklyTypedInput: true,
+		ErrorUnused:      true,
+	})
+	if err == nil {
+		err = decoder.Decode(value)
+	}
+
+	if err != nil {
+		return FeatureFlag{}, fmt.Errorf("feature flag '%s' is invalid: %w", name, err)
+	}
+
+	flag := FeatureFlag{
+		Name:    name,
+		Type:    featureFlagTypeBoolean,
+		Enabled: flagConfig.Enabled == nil || *flagConfig.Enabled,
+	}
+
+	switch {
+	case len(flagConfig.Variants) > 0:
+		total := 0
+		for variant, weight := range flagConfig.Variants {
+			if weight < 0 {
+				return FeatureFlag{}, fmt.Errorf("feature flag '%s' variant '%s' has negative weight %d", name, variant, weight)
+			}
+
+			total += weight
+		}
+
+		if total == 0 {
+			return FeatureFlag{}, fmt.Errorf("feature flag '%s' variants have no weight", name)
+		}
+
+		if _, found := flagConfig.Variants[flagConfig.Default]; flagConfig.Default != "" && !found {
+			return FeatureFlag{}, fmt.Errorf("feature flag '%s' default '%s' is not one of its variants", name, flagConfig.Default)
+		}
+
+		flag.Type = featureFlagTypeVariant
+		flag.Variants = flagConfig.Variants
+		flag.Default = flagConfig.Default
+	case flagConfig.Percentage != nil:
+		if *flagConfig.Percentage < 0 || *flagConfig.Percentage > 100 {
+			return FeatureFlag{}, fmt.Errorf("feature flag '%s' percentage %v is not between 0 and 100", name, *flagConfig.Percentage)
+		}
+
+		flag.Type = featureFlagTypePercentage
+		flag.Percentage = *flagConfig.Percentage
+	}
+
+	return flag, nil
+}
+
+func parseFeatureFlags(config ConfigService) (map[string]FeatureFlag, error) {
+	definitions := config.GetStringMap(featureFlagsConfigKey)
+	flags := make(map[string]FeatureFlag, len(definitions))
+
+	for name, value := range definitions {
+		flag, err := parseFeatureFlag(name, value)
+		if err != nil {
+			return nil, err
+		}
+
+		flags[name] = flag
+	}
+
+	return flags, nil
+}
+
+func validateFeatureFlags(config ConfigService) error {
+	if _, err := parseFeatureFlags(config); err != nil {
+		return &ConfigValidationError{Prefix: featureFlagsConfigKey, Issues: []string{err.Error()}}
+	}
+
+	return nil
+}
+
+func featureFlagBucket(name, key string) float64 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name + ":" + key))
+
+	return float64(hash.Sum32()%featureFlagBuckets) * 100 / featureFlagBuckets
+}
+
+func pickVariant(flag FeatureFlag, key string) string {
+	variants := lo.Keys(flag.Variants)
+	slices.SortFunc(variants, cmp.Compare[string])
+
+	total := lo.Sum(lo.Values(flag.Variants))
+	bucket := featureFlagBucket(flag.Name, key) * float64(total) / 100
+
+	cumulative := 0
+	for _, variant := range variants {
+		cumulative += flag.Variants[variant]
+		if bucket < float64(cumulative) {
+			return variant
+		}
+	}
+
+	return flag.Default
+}
+
+func (f *featureFlags) Evaluate(name, key string) FeatureFlagEvaluation {
+	name = strings.ToLower(name)
+
+	flag, found := (*f.flags.Load())[name]
+	if !found {
+		return FeatureFlagEvaluation{Name: name}
+	}
+
+	if !flag.Enabled {
+		return FeatureFlagEvaluation{Name: name, Variant: flag.Default}
+	}
+
+	switch flag.Type {
+	case featureFlagTypePercentage:
+		return FeatureFlagEvaluation{Name: name, Enabled: featureFlagBucket(name, key) < flag.Percentage}
+	case featureFlagTypeVariant:
+		return FeatureFlagEvaluation{Name: name, Enabled: true, Variant: pickVariant(flag, key)}
+	default:
+		return FeatureFlagEvaluation{Name: name, Enabled: true}
+	}
+}
+
+func (f *featureFlags) IsEnabled(name, key string) bool {
+	return f.Evaluate(name, key).Enabled
+}
+
+func (f *featureFlags) Variant(name, key string) string {
+	return f.Evaluate(name, key).Variant
+}
+
+func (f *featureFlags) Flags() []FeatureFlag {
+	flags := lo.Values(*f.flags.Load())
+	slices.SortFunc(flags, func(a, b FeatureFlag) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return flags
+}
+
+// ApplyFeatureFlagKey sets the key IsFeatureEnabled and FeatureVariant evaluate with, e.g. a user or
+// tenant id; without it the request's TRACE_ID is used.
+func ApplyFeatureFlagKey(keyFunc func(ginCtx *gin.Context) string) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if key := keyFunc(ginCtx); key != "" {
+			ginCtx.Set(featureFlagKeyContextKey, key)
+		}
+
+		ginCtx.Next()
+	}
+}
+
+func featureFlagKey(ginCtx *gin.Context) string {
+	if key := ginCtx.GetString(featureFlagKeyContextKey); key != "" {
+		return key
+	}
+
+	return ginCtx.GetString("TRACE_ID")
+}
+
+func IsFeatureEnabled(ginCtx *gin.Context, name string) bool {
+	return appFromContext(ginCtx).FeatureFlags().IsEnabled(name, featureFlagKey(ginCtx))
+}
+
+func FeatureVariant(ginCtx *gin.Context, name string) string {
+	return appFromContext(ginCtx).FeatureFlags().Variant(name, featureFlagKey(ginCtx))
+}
@@ -0,0 +1,112 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+const featureFlagSampleKeys = 20000
+
+func TestFeatureFlagBucket(t *testing.T) {
+	tests := []struct {
+		name       string
+		percentage float64
+	}{
+		{name: "checkout", percentage: 10},
+		{name: "search", percentage: 25},
+		{name: "beta", percentage: 50},
+		{name: "rollout", percentage: 90},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			enabled := 0
+
+			for i := 0; i < featureFlagSampleKeys; i++ {
+				key := fmt.Sprintf("user-%d", i)
+
+				bucket := featureFlagBucket(test.name, key)
+				if bucket < 0 || bucket >= 100 {
+					t.Fatalf("expected a bucket in [0, 100), got %f for %s", bucket, key)
+				}
+
+				if bucket != featureFlagBucket(test.name, key) {
+					t.Fatalf("expected a stable bucket for %s", key)
+				}
+
+				if bucket < test.percentage {
+					enabled++
+				}
+			}
+
+			share := float64(enabled) * 100 / featureFlagSampleKeys
+			if math.Abs(share-test.percentage) > 2 {
+				t.Errorf("expected about %.0f%% of keys enabled, got %.2f%%", test.percentage, share)
+			}
+		})
+	}
+}
+
+func TestFeatureFlagBucketDependsOnTheFlag(t *testing.T) {
+	same := 0
+
+	for i := 0; i < featureFlagSampleKeys; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		if (featureFlagBucket("first", key) < 50) == (featureFlagBucket("second", key) < 50) {
+			same++
+		}
+	}
+
+	if share := float64(same) * 100 / featureFlagSampleKeys; math.Abs(share-50) > 2 {
+		t.Errorf("expected flags to bucket keys independently, %.2f%% of keys landed on the same side", share)
+	}
+}
+
+func TestPickVariant(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants map[string]int
+	}{
+		{name: "even", variants: map[string]int{"control": 50, "treatment": 50}},
+		{name: "weighted", variants: map[string]int{"control": 80, "treatment": 20}},
+		{name: "three way", variants: map[string]int{"a": 1, "b": 1, "c": 2}},
+		{name: "single", variants: map[string]int{"only": 5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flag := FeatureFlag{Name: test.name, Type: featureFlagTypeVariant, Enabled: true, Variants: test.variants, Default: "fallback"}
+
+			total := 0
+			for _, weight := range test.variants {
+				total += weight
+			}
+
+			counts := map[string]int{}
+			for i := 0; i < featureFlagSampleKeys; i++ {
+				key := fmt.Sprintf("user-%d", i)
+
+				variant := pickVariant(flag, key)
+				if variant != pickVariant(flag, key) {
+					t.Fatalf("expected a stable variant for %s", key)
+				}
+
+				counts[variant]++
+			}
+
+			for variant, weight := range test.variants {
+				want := float64(weight) * 100 / float64(total)
+				if share := float64(counts[variant]) * 100 / featureFlagSampleKeys; math.Abs(share-want) > 2 {
+					t.Errorf("expected about %.0f%% of keys on %s, got %.2f%%", want, variant, share)
+				}
+			}
+
+			if counts["fallback"] != 0 {
+				t.Errorf("expected every key to land on a weighted variant, %d used the default", counts["fallback"])
+			}
+		})
+	}
+}
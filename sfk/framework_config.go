@@ -0,0 +1,97 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"github.com/samber/lo"
+	"time"
+)
+
+type rateLimiterConfig struct {
+	RateLimitCallsPerSec int `mapstructure:"rateLimitCallsPerSec" validate:"required,gt=0"`
+}
+
+type memoryLimitConfig struct {
+	MaxMemoryLimitInMB int64 `mapstructure:"maxMemoryLimitInMB" validate:"gte=0"`
+}
+
+type loggerConfig struct {
+	LogLevel string `mapstructure:"logLevel" validate:"omitempty,oneof=trace debug info warn error fatal panic disabled"`
+}
+
+type logSinkConfig struct {
+	Output      string        `mapstructure:"output" validate:"required,notBlank"`
+	Format      string        `mapstructure:"format" validate:"omitempty,oneof=json console"`
+	MaxSizeMB   int           `mapstructure:"maxSizeMB" validate:"gte=0"`
+	RotateEvery time.Duration `mapstructure:"rotateEvery" validate:"gte=0"`
+	MaxBackups  int           `mapstructure:"maxBackups" validate:"gte=0"`
+	MaxAgeDays  int           `mapstructure:"maxAgeDays" validate:"gte=0"`
+	Compress    bool          `mapstructure:"compress"`
+	BufferSize  int           `mapstructure:"bufferSize" validate:"gte=0"`
+}
+
+type logConfig struct {
+	Log struct {
+		Format string            `mapstructure:"format" validate:"omitempty,oneof=json console"`
+		Output string            `mapstructure:"output"`
+		Sinks  []logSinkConfig   `mapstructure:"sinks" validate:"dive"`
+		Levels map[string]string `mapstructure:"levels" validate:"dive,oneof=trace debug info warn error fatal panic disabled"`
+	} `mapstructure:"log"`
+}
+
+type requestTimeoutConfig struct {
+	RequestTimeout     time.Duration `mapstructure:"requestTimeout" validate:"omitempty,gte=1ms"`
+	RequestTimeoutSecs int           `mapstructure:"requestTimeoutSecs" validate:"gte=0"`
+}
+
+type shutdownConfig struct {
+	GracefulShutdownSecs int           `mapstructure:"gracefulShutdownSecs" validate:"gte=0"`
+	GracefulShutdown     time.Duration `mapstructure:"gracefulShutdown" validate:"gte=0"`
+}
+
+type secretsConfig struct {
+	Secrets struct {
+		CacheTTL time.Duration `mapstructure:"cacheTTL" validate:"gte=0"`
+		TokenTTL time.Duration `mapstructure:"tokenTTL" validate:"gte=0"`
+	} `mapstructure:"secrets"`
+}
+
+type hashicorpConfig struct {
+	Hashicorp struct {
+		OrganizationId string `mapstructure:"organizationId" validate:"required,notBlank"`
+		ProjectId      string `mapstructure:"projectId" validate:"required,notBlank"`
+	} `mapstructure:"hashicorp"`
+	ClientIds struct {
+		Hashicorp string `mapstructure:"hashicorp" validate:"required,notBlank"`
+	} `mapstructure:"clientIds"`
+}
+
+var secretServiceConfigKeys = []string{"hashicorp", "clientIds", "pprofSecret", "tls.certSecret", "tls.keySecret"}
+
+func usesSecretService(app *App) bool {
+	if app.options.SecretService != nil {
+		return false
+	}
+
+	v := app.Config().GetViper()
+
+	return lo.SomeBy(secretServiceConfigKeys, v.IsSet) || hasSecretReferences(v.AllSettings())
+}
+
+func registerFrameworkConfig(app *App, rateLimiterEnabled bool) {
+	RegisterConfig[memoryLimitConfig](app, "")
+	RegisterConfig[loggerConfig](app, "")
+	RegisterConfig[logConfig](app, "")
+	RegisterConfig[requestTimeoutConfig](app, "")
+	RegisterConfig[shutdownConfig](app, "")
+	RegisterConfig[secretsConfig](app, "")
+	app.registerConfigBinding(configBinding{prefix: featureFlagsConfigKey, validate: validateFeatureFlags})
+
+	if rateLimiterEnabled {
+		RegisterConfig[rateLimiterConfig](app, "")
+	}
+
+	if usesSecretService(app) {
+		RegisterConfig[hashicorpConfig](app, "")
+	}
+}
@@ -0,0 +1,362 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	listenFdsStart   = 3
+	readyFdEnvKey    = "SFK_READY_FD"
+	listenFdsEnvKey  = "LISTEN_FDS"
+	listenPidEnvKey  = "LISTEN_PID"
+	listenNameEnvKey = "LISTEN_FDNAMES"
+	packetConnPrefix = "udp/"
+)
+
+// GracefulRestartService restarts without downtime: with restart.enabled, restart.signal spawns the new
+// binary with the listening sockets (LISTEN_FDS), waits up to restart.readyTimeoutSecs for it to report
+// readiness and then drains this process. Sockets inherited through systemd socket activation are matched
+// by name, otherwise in order by network for the main listen addresses only, never the admin listener.
+// Startup fails when inherited sockets remain but none matches the network of a listen address.
+type GracefulRestartService interface {
+	listen(network, address string, positional bool) (net.Listener, error)
+	listenPacket(network, address string) (net.PacketConn, error)
+	restartSignal() (os.Signal, bool)
+	restart() error
+	notifyReady()
+}
+
+type namedListener struct {
+	name       string
+	listener   net.Listener
+	packetConn net.PacketConn
+}
+
+type gracefulRestartService struct {
+	config    ConfigService
+	logger    *zerolog.Logger
+	inherited []*namedListener
+	listeners []namedListener
+	mtx       sync.Mutex
+}
+
+func newGracefulRestartService(config ConfigService, logger LoggerService) GracefulRestartService {
+	service := &gracefulRestartService{
+		config: config,
+		logger: logger.ZeroLogger(),
+	}
+
+	service.inherited = service.inheritListeners()
+
+	return service
+}
+
+func (g *gracefulRestartService) inheritListeners() []*namedListener {
+	count, err := strconv.Atoi(os.Getenv(listenFdsEnvKey))
+	if err != nil || count <= 0 {
+		return nil
+	}
+
+	if pid := os.Getenv(listenPidEnvKey); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil
+	}
+
+	names := strings.Split(os.Getenv(listenNameEnvKey), ":")
+	listeners := make([]*namedListener, 0, count)
+
+	for i := 0; i < count; i++ {
+		name := ""
+		if i < len(names) {
+			name, _ = url.QueryUnescape(names[i])
+		}
+
+		file := os.NewFile(uintptr(listenFdsStart+i), name)
+		inherited, err := inheritFile(name, file)
+		_ = file.Close()
+
+		if err != nil {
+			g.logger.Error().Err(err).Msgf("Failed to inherit listener fd %d", listenFdsStart+i)
+			continue
+		}
+
+		listeners = append(listeners, inherited)
+	}
+
+	_ = os.Unsetenv(listenFdsEnvKey)
+	_ = os.Unsetenv(listenPidEnvKey)
+	_ = os.Unsetenv(listenNameEnvKey)
+
+	g.logger.Info().Msgf("Inherited %d listeners from parent process", len(listeners))
+
+	return listeners
+}
+
+func inheritFile(name string, file *os.File) (*namedListener, error) {
+	if strings.HasPrefix(name, packetConnPrefix) {
+		packetConn, err := net.FilePacketConn(file)
+		if err != nil {
+			return nil, err
+		}
+
+		return &namedListener{name: name, packetConn: packetConn}, nil
+	}
+
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &namedListener{name: name, listener: listener}, nil
+}
+
+func (g *gracefulRestartService) takeInherited(name string) *namedListener {
+	for i, inherited := range g.inherited {
+		if inherited != nil && inherited.name == name {
+			g.inherited[i] = nil
+			return inherited
+		}
+	}
+
+	return nil
+}
+
+func (g *gracefulRestartService) takePositional(network, address string) (*namedListener, error) {
+	unclaimed := 0
+
+	for i, inherited := range g.inherited {
+		if inherited == nil || inherited.listener == nil {
+			continue
+		}
+
+		if inherited.listener.Addr().Network() == network {
+			g.inherited[i] = nil
+			return inherited, nil
+		}
+
+		unclaimed++
+	}
+
+	if unclaimed > 0 {
+		return nil, fmt.Errorf("none of the %d inherited listeners matches the %s listen address %s", unclaimed, network, address)
+	}
+
+	return nil, nil
+}
+
+func (g *gracefulRestartService) listen(network, address string, positional bool) (net.Listener, error) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	inherited := g.takeInherited(address)
+	if inherited == nil && positional {
+		var err error
+		if inherited, err = g.takePositional(network, address); err != nil {
+			return nil, err
+		}
+	}
+
+	if inherited != nil {
+		g.listeners = append(g.listeners, *inherited)
+		return inherited.listener, nil
+	}
+
+	if network == "unix" {
+		if err := removeStaleSocket(address); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	g.listeners = append(g.listeners, namedListener{name: address, listener: listener})
+
+	return listener, nil
+}
+
+func (g *gracefulRestartService) listenPacket(network, address string) (net.PacketConn, error) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	name := packetConnPrefix + address
+	if inherited := g.takeInherited(name); inherited != nil {
+		g.listeners = append(g.listeners, *inherited)
+		return inherited.packetConn, nil
+	}
+
+	packetConn, err := net.ListenPacket(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	g.listeners = append(g.listeners, namedListener{name: name, packetConn: packetConn})
+
+	return packetConn, nil
+}
+
+func (g *gracefulRestartService) restartSignal() (os.Signal, bool) {
+	if !g.config.GetBool("restart.enabled") {
+		return nil, false
+	}
+
+	name := strings.ToUpper(g.config.GetString("restart.signal"))
+	if name == "" {
+		name = "SIGUSR2"
+	}
+
+	restartSignal, found := restartSignals[name]
+	if !found {
+		g.logger.Error().Msgf("Unsupported restart signal %s, graceful restart disabled", name)
+		return nil, false
+	}
+
+	return restartSignal, true
+}
+
+func listenerFile(named namedListener) (*os.File, error) {
+	var source any = named.listener
+	if named.packetConn != nil {
+		source = named.packetConn
+	}
+
+	fileListener, ok := source.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("listener %T does not support handoff", source)
+	}
+
+	return fileListener.File()
+}
+
+func childEnv(names []string, readyFd int) []string {
+	escapedNames := lo.Map(names, func(name string, _ int) string {
+		return url.QueryEscape(name)
+	})
+
+	env := lo.Filter(os.Environ(), func(entry string, _ int) bool {
+		return !strings.HasPrefix(entry, listenFdsEnvKey+"=") &&
+			!strings.HasPrefix(entry, listenPidEnvKey+"=") &&
+			!strings.HasPrefix(entry, listenNameEnvKey+"=") &&
+			!strings.HasPrefix(entry, readyFdEnvKey+"=")
+	})
+
+	return append(env,
+		fmt.Sprintf("%s=%d", listenFdsEnvKey, len(names)),
+		fmt.Sprintf("%s=%s", listenNameEnvKey, strings.Join(escapedNames, ":")),
+		fmt.Sprintf("%s=%d", readyFdEnvKey, readyFd),
+	)
+}
+
+func (g *gracefulRestartService) restart() error {
+	g.mtx.Lock()
+	listeners := g.listeners
+	g.mtx.Unlock()
+
+	files := make([]*os.File, 0, len(listeners)+1)
+	defer func() {
+		lo.ForEach(files, func(file *os.File, _ int) {
+			_ = file.Close()
+		})
+	}()
+
+	names := make([]string, 0, len(listeners))
+	for _, named := range listeners {
+		if unixListener, ok := named.listener.(*net.UnixListener); ok {
+			unixListener.SetUnlinkOnClose(false)
+		}
+
+		file, err := listenerFile(named)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, file)
+		names = append(names, named.name)
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyReader.Close()
+
+	files = append(files, readyWriter)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = childEnv(names, listenFdsStart+len(names))
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to spawn new process: %w", err)
+	}
+
+	g.logger.Info().Msgf("Spawned new process %d, waiting for it to become ready...", cmd.Process.Pid)
+
+	_ = readyWriter.Close()
+	files = files[:len(files)-1]
+
+	readyTimeoutSecs := g.config.GetInt("restart.readyTimeoutSecs")
+	if readyTimeoutSecs <= 0 {
+		readyTimeoutSecs = 30
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		buffer := make([]byte, 1)
+		_, err := readyReader.Read(buffer)
+		ready <- err
+	}()
+
+	select {
+	case err := <-ready:
+		if err != nil {
+			_ = cmd.Process.Kill()
+			return fmt.Errorf("new process %d exited before becoming ready: %w", cmd.Process.Pid, err)
+		}
+	case <-time.After(time.Duration(readyTimeoutSecs) * time.Second):
+		_ = cmd.Process.Kill()
+		return errors.New("new process did not become ready before timeout")
+	}
+
+	g.logger.Info().Msgf("New process %d is ready, draining current process", cmd.Process.Pid)
+	_ = cmd.Process.Release()
+
+	return nil
+}
+
+func (g *gracefulRestartService) notifyReady() {
+	fd, err := strconv.Atoi(os.Getenv(readyFdEnvKey))
+	if err != nil {
+		return
+	}
+
+	_ = os.Unsetenv(readyFdEnvKey)
+
+	readyFile := os.NewFile(uintptr(fd), "ready")
+	defer readyFile.Close()
+
+	if _, err := readyFile.Write([]byte{1}); err != nil {
+		g.logger.Error().Err(err).Msg("Failed to notify parent process of readiness")
+	}
+}
@@ -0,0 +1,9 @@
+// Unpublished Work © 2024
+
+//go:build !unix
+
+package sfk
+
+import "os"
+
+var restartSignals = map[string]os.Signal{}
@@ -0,0 +1,16 @@
+// Unpublished Work © 2024
+
+//go:build unix
+
+package sfk
+
+import (
+	"os"
+	"syscall"
+)
+
+var restartSignals = map[string]os.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
@@ -0,0 +1,164 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"github.com/samber/lo"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultHealthCheckTimeout = 2 * time.Second
+
+// HealthRegistry backs /livez and /readyz, which report the registered checks as a HealthReport. Readiness
+// fails as soon as a shutdown signal is received, health.drainDelaySecs later the listeners close. With the
+// admin listener enabled both endpoints are served there only, unless health.public is set.
+type HealthRegistry interface {
+	Register(checks ...types.HealthCheck)
+	LivenessHandler() gin.HandlerFunc
+	ReadinessHandler() gin.HandlerFunc
+}
+
+type HealthCheckStatus struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Critical   bool   `json:"critical"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+type HealthReport struct {
+	Status string              `json:"status"`
+	Checks []HealthCheckStatus `json:"checks"`
+}
+
+type healthRegistry struct {
+	checks    []types.HealthCheck
+	checksMtx sync.RWMutex
+	draining  atomic.Bool
+}
+
+func newHealthRegistry() *healthRegistry {
+	return &healthRegistry{}
+}
+
+// HealthRegistryInstance returns the health registry of the current App, e.g. to register checks from the
+// Database hook; ServerOptions.HealthChecks registers them up front.
+func HealthRegistryInstance() HealthRegistry {
+	return currentApp().Health()
+}
+
+func (h *healthRegistry) Register(checks ...types.HealthCheck) {
+	h.checksMtx.Lock()
+	defer h.checksMtx.Unlock()
+
+	h.checks = append(h.checks, checks...)
+}
+
+func (h *healthRegistry) markDraining() {
+	h.draining.Store(true)
+}
+
+func runHealthCheck(ctx context.Context, check types.HealthCheck) HealthCheckStatus {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+	result := make(chan error, 1)
+
+	go func() {
+		result <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	status := HealthCheckStatus{
+		Name:       check.Name,
+		Status:     "ok",
+		Critical:   check.Critical,
+		DurationMs: time.Since(startedAt).Milliseconds(),
+	}
+
+	if err != nil {
+		status.Status = "failing"
+		status.Error = err.Error()
+	}
+
+	return status
+}
+
+func (h *healthRegistry) run(ctx context.Context, livenessOnly bool) HealthReport {
+	h.checksMtx.RLock()
+	checks := lo.Filter(h.checks, func(check types.HealthCheck, _ int) bool {
+		return !livenessOnly || check.Liveness
+	})
+	h.checksMtx.RUnlock()
+
+	statuses := make([]HealthCheckStatus, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			statuses[i] = runHealthCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	failing := lo.SomeBy(statuses, func(status HealthCheckStatus) bool {
+		return status.Critical && status.Status != "ok"
+	})
+
+	return HealthReport{
+		Status: lo.Ternary(failing, "failing", "ok"),
+		Checks: statuses,
+	}
+}
+
+func writeHealthReport(ginCtx *gin.Context, report HealthReport) {
+	statusCode := http.StatusOK
+	if report.Status != "ok" {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	ginCtx.JSON(statusCode, report)
+}
+
+func (h *healthRegistry) LivenessHandler() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		writeHealthReport(ginCtx, h.run(ginCtx.Request.Context(), true))
+	}
+}
+
+func (h *healthRegistry) ReadinessHandler() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if h.draining.Load() {
+			writeHealthReport(ginCtx, HealthReport{Status: "draining", Checks: []HealthCheckStatus{}})
+			return
+		}
+
+		writeHealthReport(ginCtx, h.run(ginCtx.Request.Context(), false))
+	}
+}
+
+func registerHealthEndpoints(router *gin.Engine, healthRegistry HealthRegistry) {
+	router.GET("/livez", healthRegistry.LivenessHandler())
+	router.GET("/readyz", healthRegistry.ReadinessHandler())
+}
@@ -0,0 +1,131 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/rs/zerolog"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"net"
+	"net/http"
+)
+
+// HTTPProtocolsService adds HTTP/2 cleartext (http2.h2c) and the experimental HTTP/3 listener
+// (http3.enabled), which serves QUIC on the TLS port or http3.port and is advertised through Alt-Svc.
+type HTTPProtocolsService interface {
+	configure(server *http.Server, tlsEnabled bool) error
+	startHTTP3(restart GracefulRestartService, address string, handler http.Handler, tlsConfig *tls.Config) error
+	shutdown(ctx context.Context) error
+}
+
+type httpProtocolsService struct {
+	h2cEnabled           bool
+	http3Enabled         bool
+	http3Port            string
+	maxConcurrentStreams uint32
+	logger               *zerolog.Logger
+	http3Server          *http3.Server
+}
+
+func newHTTPProtocolsService(app *App, enableH2C, enableHTTP3 bool) HTTPProtocolsService {
+	config := app.Config()
+
+	return &httpProtocolsService{
+		h2cEnabled:           enableH2C || config.GetBool("http2.h2c"),
+		http3Enabled:         enableHTTP3 || config.GetBool("http3.enabled"),
+		http3Port:            config.GetString("http3.port"),
+		maxConcurrentStreams: uint32(config.GetInt("http2.maxConcurrentStreams")),
+		logger:               app.Logger().ZeroLogger(),
+	}
+}
+
+func (h *httpProtocolsService) configure(server *http.Server, tlsEnabled bool) error {
+	http2Server := &http2.Server{
+		MaxConcurrentStreams: h.maxConcurrentStreams,
+		IdleTimeout:          server.IdleTimeout,
+	}
+
+	if err := http2.ConfigureServer(server, http2Server); err != nil {
+		return err
+	}
+
+	if h.h2cEnabled && !tlsEnabled {
+		server.Handler = h2c.NewHandler(server.Handler, http2Server)
+	}
+
+	if h.http3Enabled {
+		if !tlsEnabled {
+			return errors.New("HTTP/3 requires TLS to be configured")
+		}
+
+		handler := server.Handler
+		server.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if h.http3Server != nil {
+				_ = h.http3Server.SetQUICHeaders(w.Header())
+			}
+
+			handler.ServeHTTP(w, req)
+		})
+	}
+
+	return nil
+}
+
+func (h *httpProtocolsService) startHTTP3(restart GracefulRestartService, address string, handler http.Handler, tlsConfig *tls.Config) error {
+	if !h.http3Enabled {
+		return nil
+	}
+
+	if address == "" {
+		return errors.New("HTTP/3 requires a tcp listen address")
+	}
+
+	if h.http3Port != "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+
+		address = net.JoinHostPort(host, h.http3Port)
+	}
+
+	packetConn, err := restart.listenPacket("udp", address)
+	if err != nil {
+		return err
+	}
+
+	quicConfig := &quic.Config{}
+	if h.maxConcurrentStreams > 0 {
+		quicConfig.MaxIncomingStreams = int64(h.maxConcurrentStreams)
+	}
+
+	h.http3Server = &http3.Server{
+		Addr:       address,
+		Handler:    handler,
+		TLSConfig:  tlsConfig,
+		QUICConfig: quicConfig,
+	}
+
+	go func() {
+		h.logger.Info().Msgf("HTTP/3 server running successfully on udp %s", address)
+
+		if err := h.http3Server.Serve(packetConn); err != nil && !errors.Is(err, http.ErrServerClosed) && !errors.Is(err, quic.ErrServerClosed) {
+			h.logger.Fatal().Msgf("Failed to start HTTP/3 server... listen: %+v\n", err)
+		}
+	}()
+
+	return nil
+}
+
+func (h *httpProtocolsService) shutdown(ctx context.Context) error {
+	if h.http3Server == nil {
+		return nil
+	}
+
+	return h.http3Server.Shutdown(ctx)
+}
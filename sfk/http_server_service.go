@@ -0,0 +1,151 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"golang.org/x/net/netutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultReadTimeout       = 30 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+)
+
+type HTTPServerService interface {
+	newServer(address string, handler http.Handler) *http.Server
+	wrapListener(listener net.Listener) net.Listener
+}
+
+type httpServerService struct {
+	options            *types.HTTPServerOptions
+	logConnectionState bool
+	logger             *zerolog.Logger
+}
+
+// durationOption returns the ServerOptions value when set, where a negative value disables the timeout,
+// and the fallback from config otherwise.
+func durationOption(value, fallback time.Duration) time.Duration {
+	switch {
+	case value < 0:
+		return 0
+	case value > 0:
+		return value
+	}
+
+	return fallback
+}
+
+func secondsOr(config ConfigService, key string, fallback time.Duration) time.Duration {
+	if !config.IsSet(key) {
+		return fallback
+	}
+
+	if secs := config.GetInt(key); secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+
+	return fallback
+}
+
+// httpServerOptionsFromConfig reads the server.* options. Without an explicit server.writeTimeoutSecs the write
+// timeout is disabled when pprof is served by the main server, since it would cut off profiles like
+// /metrics/pprof/profile?seconds=60 (pprof rejects durations above the WriteTimeout).
+func httpServerOptionsFromConfig(config ConfigService, servesPprof bool) *types.HTTPServerOptions {
+	maxHeaderBytes := config.GetInt("server.maxHeaderBytes")
+	if maxHeaderBytes <= 0 {
+		maxHeaderBytes = http.DefaultMaxHeaderBytes
+	}
+
+	return &types.HTTPServerOptions{
+		ReadHeaderTimeout: secondsOr(config, "server.readHeaderTimeoutSecs", defaultReadHeaderTimeout),
+		ReadTimeout:       secondsOr(config, "server.readTimeoutSecs", defaultReadTimeout),
+		WriteTimeout:      secondsOr(config, "server.writeTimeoutSecs", lo.Ternary(servesPprof, 0, defaultWriteTimeout)),
+		IdleTimeout:       secondsOr(config, "server.idleTimeoutSecs", defaultIdleTimeout),
+		MaxHeaderBytes:    maxHeaderBytes,
+		DisableKeepAlives: config.GetBool("server.disableKeepAlives"),
+		MaxConnections:    config.GetInt("server.maxConnections"),
+	}
+}
+
+func mergeHTTPServerOptions(defaults *types.HTTPServerOptions, overrides *types.HTTPServerOptions) *types.HTTPServerOptions {
+	if overrides == nil {
+		return defaults
+	}
+
+	merged := *defaults
+
+	merged.ReadHeaderTimeout = durationOption(overrides.ReadHeaderTimeout, defaults.ReadHeaderTimeout)
+	merged.ReadTimeout = durationOption(overrides.ReadTimeout, defaults.ReadTimeout)
+	merged.WriteTimeout = durationOption(overrides.WriteTimeout, defaults.WriteTimeout)
+	merged.IdleTimeout = durationOption(overrides.IdleTimeout, defaults.IdleTimeout)
+
+	if overrides.MaxHeaderBytes > 0 {
+		merged.MaxHeaderBytes = overrides.MaxHeaderBytes
+	}
+
+	if overrides.MaxConnections != 0 {
+		merged.MaxConnections = max(overrides.MaxConnections, 0)
+	}
+
+	merged.DisableKeepAlives = merged.DisableKeepAlives || overrides.DisableKeepAlives
+	merged.ConnStateHook = overrides.ConnStateHook
+
+	return &merged
+}
+
+func newHTTPServerService(app *App, options *types.HTTPServerOptions, servesPprof bool) HTTPServerService {
+	config := app.Config()
+
+	return &httpServerService{
+		options:            mergeHTTPServerOptions(httpServerOptionsFromConfig(config, servesPprof), options),
+		logConnectionState: config.GetBool("server.logConnectionState"),
+		logger:             app.Logger().ZeroLogger(),
+	}
+}
+
+func (h *httpServerService) connState(conn net.Conn, state http.ConnState) {
+	if h.logConnectionState {
+		h.logger.Info().
+			Str("remoteAddress", conn.RemoteAddr().String()).
+			Str("localAddress", conn.LocalAddr().String()).
+			Str("state", state.String()).
+			Msg("Connection state changed")
+	}
+
+	if h.options.ConnStateHook != nil {
+		h.options.ConnStateHook(conn, state)
+	}
+}
+
+func (h *httpServerService) newServer(address string, handler http.Handler) *http.Server {
+	server := &http.Server{
+		Addr:              address,
+		Handler:           handler,
+		ReadHeaderTimeout: h.options.ReadHeaderTimeout,
+		ReadTimeout:       h.options.ReadTimeout,
+		WriteTimeout:      h.options.WriteTimeout,
+		IdleTimeout:       h.options.IdleTimeout,
+		MaxHeaderBytes:    h.options.MaxHeaderBytes,
+		ConnState:         h.connState,
+	}
+
+	server.SetKeepAlivesEnabled(!h.options.DisableKeepAlives)
+
+	return server
+}
+
+func (h *httpServerService) wrapListener(listener net.Listener) net.Listener {
+	if h.options.MaxConnections > 0 {
+		return netutil.LimitListener(listener, h.options.MaxConnections)
+	}
+
+	return listener
+}
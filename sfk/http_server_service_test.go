@@ -0,0 +1,66 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"testing"
+	"time"
+)
+
+func TestMergeHTTPServerOptions(t *testing.T) {
+	defaults := &types.HTTPServerOptions{
+		ReadHeaderTimeout: defaultReadHeaderTimeout,
+		ReadTimeout:       defaultReadTimeout,
+		WriteTimeout:      defaultWriteTimeout,
+		IdleTimeout:       defaultIdleTimeout,
+		MaxConnections:    100,
+	}
+
+	tests := []struct {
+		name      string
+		overrides *types.HTTPServerOptions
+		want      types.HTTPServerOptions
+	}{
+		{
+			name: "no overrides",
+			want: *defaults,
+		},
+		{
+			name:      "zero values keep the config",
+			overrides: &types.HTTPServerOptions{},
+			want:      *defaults,
+		},
+		{
+			name:      "positive values override the config",
+			overrides: &types.HTTPServerOptions{WriteTimeout: time.Minute, MaxConnections: 10},
+			want: types.HTTPServerOptions{
+				ReadHeaderTimeout: defaultReadHeaderTimeout,
+				ReadTimeout:       defaultReadTimeout,
+				WriteTimeout:      time.Minute,
+				IdleTimeout:       defaultIdleTimeout,
+				MaxConnections:    10,
+			},
+		},
+		{
+			name:      "negative values disable",
+			overrides: &types.HTTPServerOptions{ReadTimeout: -1, WriteTimeout: -1, MaxConnections: -1},
+			want: types.HTTPServerOptions{
+				ReadHeaderTimeout: defaultReadHeaderTimeout,
+				IdleTimeout:       defaultIdleTimeout,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := mergeHTTPServerOptions(defaults, test.overrides)
+
+			if merged.ReadHeaderTimeout != test.want.ReadHeaderTimeout || merged.ReadTimeout != test.want.ReadTimeout ||
+				merged.WriteTimeout != test.want.WriteTimeout || merged.IdleTimeout != test.want.IdleTimeout ||
+				merged.MaxConnections != test.want.MaxConnections {
+				t.Errorf("expected %+v, got %+v", test.want, *merged)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"slices"
+	"sync"
+	"time"
+)
+
+// LifecycleService holds named, prioritised hooks. Start hooks run in ascending priority before the server
+// listens, bounded by lifecycle.startTimeoutSecs, and any error fails startup. Stop hooks run in reverse
+// order once the listeners drained, even when draining timed out, bounded by lifecycle.stopTimeoutSecs.
+type LifecycleService interface {
+	Append(hooks ...types.LifecycleHook)
+	OnStart(name string, priority int, onStart func(ctx context.Context) error)
+	OnStop(name string, priority int, onStop func(ctx context.Context) error)
+}
+
+type lifecycleService struct {
+	hooks      []types.LifecycleHook
+	hooksMtx   sync.RWMutex
+	started    []types.LifecycleHook
+	startedMtx sync.Mutex
+	logger     *zerolog.Logger
+}
+
+func newLifecycleService(logger LoggerService) *lifecycleService {
+	return &lifecycleService{
+		logger: logger.ZeroLogger(),
+	}
+}
+
+func LifecycleServiceInstance() LifecycleService {
+	return currentApp().Lifecycle()
+}
+
+func (l *lifecycleService) Append(hooks ...types.LifecycleHook) {
+	l.hooksMtx.Lock()
+	defer l.hooksMtx.Unlock()
+
+	l.hooks = append(l.hooks, hooks...)
+}
+
+func (l *lifecycleService) OnStart(name string, priority int, onStart func(ctx context.Context) error) {
+	l.Append(types.LifecycleHook{Name: name, Priority: priority, OnStart: onStart})
+}
+
+func (l *lifecycleService) OnStop(name string, priority int, onStop func(ctx context.Context) error) {
+	l.Append(types.LifecycleHook{Name: name, Priority: priority, OnStop: onStop})
+}
+
+func (l *lifecycleService) orderedHooks() []types.LifecycleHook {
+	l.hooksMtx.RLock()
+	defer l.hooksMtx.RUnlock()
+
+	hooks := slices.Clone(l.hooks)
+	slices.SortStableFunc(hooks, func(a, b types.LifecycleHook) int {
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+
+	return hooks
+}
+
+func (l *lifecycleService) runHook(ctx context.Context, phase string, name string, hook func(ctx context.Context) error) error {
+	startedAt := time.Now()
+	err := hook(ctx)
+	duration := time.Since(startedAt)
+
+	if err != nil {
+		l.logger.Error().Err(err).Str("hook", name).Dur("duration", duration).Msgf("Lifecycle %s hook %s failed", phase, name)
+		return fmt.Errorf("lifecycle %s hook %s failed: %w", phase, name, err)
+	}
+
+	l.logger.Info().Str("hook", name).Dur("duration", duration).Msgf("Lifecycle %s hook %s completed", phase, name)
+
+	return nil
+}
+
+func (l *lifecycleService) start(ctx context.Context) error {
+	for _, hook := range l.orderedHooks() {
+		if hook.OnStart != nil {
+			if err := l.runHook(ctx, "start", hook.Name, hook.OnStart); err != nil {
+				return err
+			}
+		}
+
+		l.startedMtx.Lock()
+		l.started = append(l.started, hook)
+		l.startedMtx.Unlock()
+	}
+
+	return nil
+}
+
+func (l *lifecycleService) stop(ctx context.Context) error {
+	l.startedMtx.Lock()
+	hooks := lo.Reverse(l.started)
+	l.started = nil
+	l.startedMtx.Unlock()
+
+	var errs []error
+	for _, hook := range hooks {
+		if hook.OnStop != nil {
+			errs = append(errs, l.runHook(ctx, "stop", hook.Name, hook.OnStop))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,105 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultSocketMode os.FileMode = 0660
+
+type listenAddress struct {
+	network string
+	address string
+	mode    os.FileMode
+}
+
+func (l listenAddress) String() string {
+	if l.network == "unix" {
+		return "unix:" + l.address
+	}
+
+	return l.address
+}
+
+func parseListenAddress(spec string) (listenAddress, error) {
+	spec = strings.TrimSpace(spec)
+
+	if rest, ok := strings.CutPrefix(spec, "unix:"); ok {
+		path, query, _ := strings.Cut(strings.TrimPrefix(rest, "//"), "?")
+		if path == "" {
+			return listenAddress{}, fmt.Errorf(`listen address "%s" has no socket path`, spec)
+		}
+
+		mode := defaultSocketMode
+		if query != "" {
+			values, err := url.ParseQuery(query)
+			if err != nil {
+				return listenAddress{}, fmt.Errorf(`listen address "%s" has invalid options: %w`, spec, err)
+			}
+
+			if rawMode := values.Get("mode"); rawMode != "" {
+				parsedMode, err := strconv.ParseUint(rawMode, 8, 32)
+				if err != nil {
+					return listenAddress{}, fmt.Errorf(`listen address "%s" has invalid mode: %w`, spec, err)
+				}
+
+				mode = os.FileMode(parsedMode)
+			}
+		}
+
+		return listenAddress{network: "unix", address: path, mode: mode}, nil
+	}
+
+	address := strings.TrimPrefix(spec, "tcp://")
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return listenAddress{}, fmt.Errorf(`listen address "%s" is invalid: %w`, spec, err)
+	}
+
+	return listenAddress{network: "tcp", address: address}, nil
+}
+
+func parseListenAddresses(specs []string) ([]listenAddress, error) {
+	addresses := make([]listenAddress, 0, len(specs))
+
+	for _, spec := range specs {
+		address, err := parseListenAddress(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a unix socket", path)
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("unix socket %s is already in use by another process", path)
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,65 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseListenAddress(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    listenAddress
+		wantErr bool
+	}{
+		{spec: ":8083", want: listenAddress{network: "tcp", address: ":8083"}},
+		{spec: " 127.0.0.1:8083 ", want: listenAddress{network: "tcp", address: "127.0.0.1:8083"}},
+		{spec: "tcp://[::1]:8083", want: listenAddress{network: "tcp", address: "[::1]:8083"}},
+		{spec: "unix:/run/app.sock", want: listenAddress{network: "unix", address: "/run/app.sock", mode: defaultSocketMode}},
+		{spec: "unix:///run/app.sock", want: listenAddress{network: "unix", address: "/run/app.sock", mode: defaultSocketMode}},
+		{spec: "unix:/run/app.sock?mode=0600", want: listenAddress{network: "unix", address: "/run/app.sock", mode: os.FileMode(0600)}},
+		{spec: "8083", wantErr: true},
+		{spec: "tcp://localhost", wantErr: true},
+		{spec: "unix:", wantErr: true},
+		{spec: "unix:/run/app.sock?mode=rw", wantErr: true},
+		{spec: "unix:/run/app.sock?mode=%zz", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			address, err := parseListenAddress(test.spec)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an invalid listen address error, got %+v", address)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if address != test.want {
+				t.Errorf("expected %+v, got %+v", test.want, address)
+			}
+		})
+	}
+}
+
+func TestParseListenAddresses(t *testing.T) {
+	addresses, err := parseListenAddresses([]string{":8083", "unix:/run/app.sock"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(addresses) != 2 || addresses[0].String() != ":8083" || addresses[1].String() != "unix:/run/app.sock" {
+		t.Errorf("expected the tcp and unix addresses in order, got %+v", addresses)
+	}
+
+	if _, err := parseListenAddresses([]string{":8083", "invalid"}); err == nil {
+		t.Error("expected one invalid address to fail the list")
+	}
+}
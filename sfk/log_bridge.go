@@ -0,0 +1,217 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+	"io"
+	"log"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+type slogHandler struct {
+	logger LoggerService
+	prefix string
+	attrs  []func(event *zerolog.Event)
+}
+
+// NewSlogHandler routes log/slog through logger, with env, the caller and, for slog.*Context calls inside a
+// request, the request fields such as traceId. The default App installs it as slog.Default() under the
+// slog component at startup, unless log.bridge is false.
+func NewSlogHandler(logger LoggerService) slog.Handler {
+	return &slogHandler{logger: logger}
+}
+
+func slogLevel(level slog.Level) zerolog.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return zerolog.TraceLevel
+	case level < slog.LevelInfo:
+		return zerolog.DebugLevel
+	case level < slog.LevelWarn:
+		return zerolog.InfoLevel
+	case level < slog.LevelError:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
+func callerSkipFrames(internalPrefixes ...string) int {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+
+	for skip := 1; ; skip++ {
+		frame, more := frames.Next()
+		if !hasAnyPrefix(frame.Function, internalPrefixes) {
+			return skip
+		}
+
+		if !more {
+			return 0
+		}
+	}
+}
+
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func addSlogAttr(event *zerolog.Event, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	key := prefix + attr.Key
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix = key + "."
+		}
+
+		for _, groupAttr := range attr.Value.Group() {
+			addSlogAttr(event, groupPrefix, groupAttr)
+		}
+	case slog.KindString:
+		event.Str(key, attr.Value.String())
+	case slog.KindInt64:
+		event.Int64(key, attr.Value.Int64())
+	case slog.KindUint64:
+		event.Uint64(key, attr.Value.Uint64())
+	case slog.KindFloat64:
+		event.Float64(key, attr.Value.Float64())
+	case slog.KindBool:
+		event.Bool(key, attr.Value.Bool())
+	case slog.KindDuration:
+		event.Dur(key, attr.Value.Duration())
+	case slog.KindTime:
+		event.Time(key, attr.Value.Time())
+	default:
+		if err, ok := attr.Value.Any().(error); ok {
+			event.AnErr(key, err)
+			return
+		}
+
+		event.Interface(key, attr.Value.Any())
+	}
+}
+
+type leveledLogger interface {
+	enabled(level zerolog.Level) bool
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if leveled, ok := h.logger.(leveledLogger); ok {
+		return leveled.enabled(slogLevel(level))
+	}
+
+	return slogLevel(level) >= h.logger.ZeroLogger().GetLevel()
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	event := h.logger.Ctx(ctx).WithLevel(slogLevel(record.Level))
+	if event == nil {
+		return nil
+	}
+
+	for _, attr := range h.attrs {
+		attr(event)
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(event, h.prefix, attr)
+		return true
+	})
+
+	event.CallerSkipFrame(callerSkipFrames("log/slog.", "log.")).Msg(record.Message)
+
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.attrs = append(handler.attrs[:len(handler.attrs):len(handler.attrs)], func(event *zerolog.Event) {
+		for _, attr := range attrs {
+			addSlogAttr(event, h.prefix, attr)
+		}
+	})
+
+	return &handler
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	handler := *h
+	handler.prefix = h.prefix + name + "."
+
+	return &handler
+}
+
+type stdLogWriter struct {
+	logger LoggerService
+}
+
+// NewStdLogWriter writes the output of the standard log package to logger. The default App points log at
+// it under the log component at startup, unless log.bridge is false.
+func NewStdLogWriter(logger LoggerService) io.Writer {
+	return &stdLogWriter{logger: logger}
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	w.logger.ZeroLogger().Info().
+		CallerSkipFrame(callerSkipFrames("log.")).
+		Msg(strings.TrimRight(string(p), "\n"))
+
+	return len(p), nil
+}
+
+type restyLogger struct {
+	logger LoggerService
+}
+
+// NewRestyLogger adapts resty clients, e.g. the secret service client under the resty component.
+func NewRestyLogger(logger LoggerService) resty.Logger {
+	return &restyLogger{logger: logger}
+}
+
+func (r *restyLogger) log(level zerolog.Level, format string, args ...any) {
+	r.logger.ZeroLogger().WithLevel(level).
+		CallerSkipFrame(callerSkipFrames("github.com/go-resty/resty/", "github.com/omkarsrepo/server-framework/sfk.(*restyLogger)")).
+		Msg(strings.TrimRight(fmt.Sprintf(format, args...), "\n"))
+}
+
+func (r *restyLogger) Errorf(format string, args ...any) {
+	r.log(zerolog.ErrorLevel, format, args...)
+}
+
+func (r *restyLogger) Warnf(format string, args ...any) {
+	r.log(zerolog.WarnLevel, format, args...)
+}
+
+func (r *restyLogger) Debugf(format string, args ...any) {
+	r.log(zerolog.DebugLevel, format, args...)
+}
+
+func installLogBridge(logger LoggerService) {
+	slog.SetDefault(slog.New(NewSlogHandler(logger.Component("slog"))))
+
+	log.SetFlags(0)
+	log.SetOutput(NewStdLogWriter(logger.Component("log")))
+}
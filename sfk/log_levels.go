@@ -0,0 +1,191 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const logLevelsConfigKey = "log.levels"
+
+type LogLevelOverride struct {
+	Component string     `json:"component,omitempty"`
+	Level     string     `json:"level"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type LogLevels struct {
+	Level      string             `json:"level"`
+	Components map[string]string  `json:"components"`
+	Overrides  []LogLevelOverride `json:"overrides"`
+}
+
+type logLevelOverride struct {
+	level     zerolog.Level
+	expiresAt time.Time
+	timer     *time.Timer
+}
+
+type logLevels struct {
+	mtx                  sync.Mutex
+	configured           zerolog.Level
+	configuredComponents map[string]zerolog.Level
+	overrides            map[string]*logLevelOverride
+	global               atomic.Int32
+	components           atomic.Pointer[map[string]zerolog.Level]
+}
+
+func newLogLevels() *logLevels {
+	levels := &logLevels{
+		configuredComponents: make(map[string]zerolog.Level),
+		overrides:            make(map[string]*logLevelOverride),
+	}
+	levels.components.Store(&map[string]zerolog.Level{})
+
+	return levels
+}
+
+func parseComponentLogLevels(settings map[string]any) (map[string]zerolog.Level, error) {
+	levels := make(map[string]zerolog.Level, len(settings))
+
+	for component, value := range settings {
+		level, err := parseLogLevel(cast.ToString(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level for component %s: %w", component, err)
+		}
+
+		levels[strings.ToLower(component)] = level
+	}
+
+	return levels, nil
+}
+
+func (l *logLevels) level(component string) zerolog.Level {
+	if component != "" {
+		if level, found := (*l.components.Load())[component]; found {
+			return level
+		}
+	}
+
+	return zerolog.Level(l.global.Load())
+}
+
+func (l *logLevels) enabled(component string, level zerolog.Level) bool {
+	return level >= l.level(component)
+}
+
+func (l *logLevels) apply() {
+	global := l.configured
+	if override, found := l.overrides[""]; found {
+		global = override.level
+	}
+
+	components := maps.Clone(l.configuredComponents)
+	for component, override := range l.overrides {
+		if component != "" {
+			components[component] = override.level
+		}
+	}
+
+	l.global.Store(int32(global))
+	l.components.Store(&components)
+}
+
+func (l *logLevels) configure(global zerolog.Level, components map[string]zerolog.Level) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.configured = global
+	l.configuredComponents = components
+	l.apply()
+}
+
+func (l *logLevels) override(component string, level zerolog.Level, duration time.Duration) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.stopOverride(component)
+
+	override := &logLevelOverride{level: level}
+	if duration > 0 {
+		override.expiresAt = time.Now().Add(duration)
+		override.timer = time.AfterFunc(duration, func() {
+			l.expire(component, override)
+		})
+	}
+
+	l.overrides[component] = override
+	l.apply()
+}
+
+func (l *logLevels) expire(component string, override *logLevelOverride) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.overrides[component] != override {
+		return
+	}
+
+	delete(l.overrides, component)
+	l.apply()
+}
+
+func (l *logLevels) stopOverride(component string) {
+	if existing, found := l.overrides[component]; found && existing.timer != nil {
+		existing.timer.Stop()
+	}
+}
+
+func (l *logLevels) reset(component string) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if _, found := l.overrides[component]; !found {
+		return false
+	}
+
+	l.stopOverride(component)
+	delete(l.overrides, component)
+	l.apply()
+
+	return true
+}
+
+func (l *logLevels) snapshot() LogLevels {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	components := make(map[string]string)
+	for component, level := range *l.components.Load() {
+		components[component] = level.String()
+	}
+
+	overrides := lo.MapToSlice(l.overrides, func(component string, override *logLevelOverride) LogLevelOverride {
+		result := LogLevelOverride{Component: component, Level: override.level.String()}
+		if !override.expiresAt.IsZero() {
+			expiresAt := override.expiresAt
+			result.ExpiresAt = &expiresAt
+		}
+
+		return result
+	})
+	slices.SortFunc(overrides, func(a, b LogLevelOverride) int {
+		return cmp.Compare(a.Component, b.Component)
+	})
+
+	return LogLevels{
+		Level:      zerolog.Level(l.global.Load()).String(),
+		Components: components,
+		Overrides:  overrides,
+	}
+}
@@ -0,0 +1,77 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// LogReopenService reopens the log files on log.reopenSignal after an external logrotate, e.g. postrotate
+// kill -HUP. When the signal equals config.reloadSignal the config reload service reopens them before the
+// reload instead, and it is disabled with a warning when it equals restart.signal.
+type LogReopenService interface {
+	start()
+	close()
+}
+
+type logReopenService struct {
+	config       ConfigService
+	logger       *loggerService
+	zerolog      *zerolog.Logger
+	restart      GracefulRestartService
+	configReload ConfigReloadService
+	signals      chan os.Signal
+}
+
+func newLogReopenService(app *App, restart GracefulRestartService, configReload ConfigReloadService) LogReopenService {
+	return &logReopenService{
+		config:       app.Config(),
+		logger:       app.loggerService(),
+		zerolog:      app.Logger().ZeroLogger(),
+		restart:      restart,
+		configReload: configReload,
+	}
+}
+
+func (l *logReopenService) start() {
+	if len(l.logger.SinkStats()) == 0 {
+		return
+	}
+
+	name := strings.ToUpper(lo.CoalesceOrEmpty(l.config.GetString("log.reopenSignal"), "SIGHUP"))
+	reopenSignal, found := restartSignals[name]
+	if !found {
+		return
+	}
+
+	if restartSignal, enabled := l.restart.restartSignal(); enabled && restartSignal == reopenSignal {
+		l.zerolog.Warn().Msgf("Log reopen signal %s is used for graceful restart, reopening log files on signal disabled", name)
+		return
+	}
+
+	if reloadName, enabled := l.configReload.signalName(); enabled && reloadName == name {
+		// the config reload listener reopens the log files before reloading
+		return
+	}
+
+	l.signals = make(chan os.Signal, 1)
+	signal.Notify(l.signals, reopenSignal)
+
+	go func() {
+		for range l.signals {
+			l.logger.reopen()
+			l.zerolog.Info().Msgf("Received %s, log files reopened", name)
+		}
+	}()
+}
+
+func (l *logReopenService) close() {
+	if l.signals != nil {
+		signal.Stop(l.signals)
+		close(l.signals)
+	}
+}
@@ -0,0 +1,392 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"cmp"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	logBackupTimeFormat      = "20060102T150405.000"
+	defaultLogSinkBufferSize = 1024
+)
+
+var errLogSinkClosed = errors.New("log sink closed")
+
+// rotatingFileWriter writes a file sink, rotating it once maxSizeMB is reached and/or every rotateEvery
+// (e.g. 24h). It keeps maxBackups rotated files for maxAgeDays days and gzips them with compress.
+type rotatingFileWriter struct {
+	path        string
+	maxSize     int64
+	rotateEvery time.Duration
+	maxBackups  int
+	maxAge      time.Duration
+	compress    bool
+	mtx         sync.Mutex
+	file        *os.File
+	size        int64
+	rotateAt    time.Time
+	maintainMtx sync.Mutex
+	maintaining sync.WaitGroup
+}
+
+func newRotatingFileWriter(sink logSinkConfig) (*rotatingFileWriter, error) {
+	writer := &rotatingFileWriter{
+		path:        sink.Output,
+		maxSize:     int64(sink.MaxSizeMB) * 1024 * 1024,
+		rotateEvery: sink.RotateEvery,
+		maxBackups:  sink.MaxBackups,
+		maxAge:      time.Duration(sink.MaxAgeDays) * 24 * time.Hour,
+		compress:    sink.Compress,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(writer.path), 0755); err != nil {
+		return nil, err
+	}
+
+	if err := writer.open(); err != nil {
+		return nil, err
+	}
+
+	if writer.maxBackups > 0 || writer.maxAge > 0 || writer.compress {
+		writer.maintaining.Add(1)
+		go writer.maintain()
+	}
+
+	return writer, nil
+}
+
+func (w *rotatingFileWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+
+	if w.rotateEvery > 0 {
+		w.rotateAt = time.Now().Truncate(w.rotateEvery).Add(w.rotateEvery)
+	}
+
+	return nil
+}
+
+func (w *rotatingFileWriter) shouldRotate(next int) bool {
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(next) > w.maxSize {
+		return true
+	}
+
+	return w.rotateEvery > 0 && !time.Now().Before(w.rotateAt)
+}
+
+func (w *rotatingFileWriter) Write(p []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.file == nil {
+		return 0, errLogSinkClosed
+	}
+
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+func (w *rotatingFileWriter) backupName(at time.Time) string {
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext)
+
+	return fmt.Sprintf("%s-%s%s", base, at.UTC().Format(logBackupTimeFormat), ext)
+}
+
+func (w *rotatingFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(w.path, w.backupName(time.Now())); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.maintaining.Add(1)
+	go w.maintain()
+
+	return nil
+}
+
+func (w *rotatingFileWriter) reopen() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.file == nil {
+		return errLogSinkClosed
+	}
+
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	return w.open()
+}
+
+type logBackup struct {
+	path string
+	at   time.Time
+}
+
+func (w *rotatingFileWriter) backups() ([]logBackup, error) {
+	ext := filepath.Ext(w.path)
+	prefix := filepath.Base(strings.TrimSuffix(w.path, ext)) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(w.path))
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]logBackup, 0)
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".gz")
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+
+		at, err := time.Parse(logBackupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, logBackup{path: filepath.Join(filepath.Dir(w.path), entry.Name()), at: at})
+	}
+
+	slices.SortFunc(backups, func(a, b logBackup) int {
+		return b.at.Compare(a.at)
+	})
+
+	return backups, nil
+}
+
+func compressLogFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	compressor := gzip.NewWriter(target)
+	if _, err := io.Copy(compressor, source); err != nil {
+		_ = target.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+
+	if err := cmp.Or(compressor.Close(), target.Close()); err != nil {
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+func (w *rotatingFileWriter) maintain() {
+	defer w.maintaining.Done()
+
+	w.maintainMtx.Lock()
+	defer w.maintainMtx.Unlock()
+
+	backups, err := w.backups()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to list log backups of %s: %s\n", w.path, err)
+		return
+	}
+
+	for i, backup := range backups {
+		expired := w.maxAge > 0 && time.Since(backup.at) > w.maxAge
+		if (w.maxBackups > 0 && i >= w.maxBackups) || expired {
+			_ = os.Remove(backup.path)
+			continue
+		}
+
+		if w.compress && !strings.HasSuffix(backup.path, ".gz") {
+			if err := compressLogFile(backup.path); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "failed to compress log backup %s: %s\n", backup.path, err)
+			}
+		}
+	}
+}
+
+func (w *rotatingFileWriter) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.maintaining.Wait()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	return err
+}
+
+// asyncWriter buffers bufferSize events in front of a file sink so requests never wait on disk I/O. Events
+// that do not fit are dropped and counted, fatal and panic events flush the buffer first.
+type asyncWriter struct {
+	writer  io.Writer
+	entries chan []byte
+	flushes chan chan struct{}
+	done    chan struct{}
+	mtx     sync.RWMutex
+	closed  bool
+	written atomic.Uint64
+	dropped atomic.Uint64
+}
+
+func newAsyncWriter(writer io.Writer, bufferSize int) *asyncWriter {
+	if bufferSize <= 0 {
+		bufferSize = defaultLogSinkBufferSize
+	}
+
+	async := &asyncWriter{
+		writer:  writer,
+		entries: make(chan []byte, bufferSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go async.run()
+
+	return async
+}
+
+func (a *asyncWriter) run() {
+	defer close(a.done)
+
+	for {
+		select {
+		case entry, ok := <-a.entries:
+			if !ok {
+				return
+			}
+
+			a.write(entry)
+		case flushed := <-a.flushes:
+			a.drain()
+			close(flushed)
+		}
+	}
+}
+
+func (a *asyncWriter) write(entry []byte) {
+	if _, err := a.writer.Write(entry); err != nil {
+		a.dropped.Add(1)
+		return
+	}
+
+	a.written.Add(1)
+}
+
+func (a *asyncWriter) drain() {
+	for {
+		select {
+		case entry := <-a.entries:
+			a.write(entry)
+		default:
+			return
+		}
+	}
+}
+
+func (a *asyncWriter) Write(p []byte) (int, error) {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	if a.closed {
+		a.dropped.Add(1)
+		return len(p), nil
+	}
+
+	select {
+	case a.entries <- slices.Clone(p):
+	default:
+		a.dropped.Add(1)
+	}
+
+	return len(p), nil
+}
+
+func (a *asyncWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < zerolog.FatalLevel || level == zerolog.NoLevel {
+		return a.Write(p)
+	}
+
+	a.flush()
+	a.write(p)
+
+	return len(p), nil
+}
+
+func (a *asyncWriter) flush() {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	if a.closed {
+		return
+	}
+
+	flushed := make(chan struct{})
+	a.flushes <- flushed
+	<-flushed
+}
+
+func (a *asyncWriter) Close() error {
+	a.mtx.Lock()
+	if a.closed {
+		a.mtx.Unlock()
+		return nil
+	}
+
+	a.closed = true
+	close(a.entries)
+	a.mtx.Unlock()
+
+	<-a.done
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readLogFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+
+	return string(content)
+}
+
+func writeLogLines(t *testing.T, writer *rotatingFileWriter, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if _, err := writer.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("failed to write %q: %v", line, err)
+		}
+	}
+}
+
+func TestRotatingFileWriterReopensAfterExternalRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	writer, err := newRotatingFileWriter(logSinkConfig{Output: path})
+	if err != nil {
+		t.Fatalf("failed to open the log file: %v", err)
+	}
+	defer writer.Close()
+
+	writeLogLines(t, writer, "before rotation")
+
+	rotated := path + ".1"
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatalf("failed to rotate the log file: %v", err)
+	}
+
+	writeLogLines(t, writer, "before reopen")
+
+	if err := writer.reopen(); err != nil {
+		t.Fatalf("failed to reopen the log file: %v", err)
+	}
+
+	writeLogLines(t, writer, "after reopen")
+
+	if content := readLogFile(t, rotated); content != "before rotation\nbefore reopen\n" {
+		t.Errorf("expected the rotated file to hold the lines written before reopening, got %q", content)
+	}
+
+	if content := readLogFile(t, path); content != "after reopen\n" {
+		t.Errorf("expected a new log file with the lines written after reopening, got %q", content)
+	}
+}
+
+func TestRotatingFileWriterRotates(t *testing.T) {
+	line := strings.Repeat("x", 400*1024)
+
+	tests := []struct {
+		name        string
+		sink        logSinkConfig
+		writes      int
+		wantBackups int
+	}{
+		{name: "below max size", sink: logSinkConfig{MaxSizeMB: 1}, writes: 2, wantBackups: 0},
+		{name: "above max size", sink: logSinkConfig{MaxSizeMB: 1}, writes: 3, wantBackups: 1},
+		{name: "no max size", sink: logSinkConfig{}, writes: 5, wantBackups: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.sink.Output = filepath.Join(t.TempDir(), "app.log")
+
+			writer, err := newRotatingFileWriter(test.sink)
+			if err != nil {
+				t.Fatalf("failed to open the log file: %v", err)
+			}
+
+			for i := 0; i < test.writes; i++ {
+				writeLogLines(t, writer, line)
+			}
+
+			if err := writer.Close(); err != nil {
+				t.Fatalf("failed to close the log file: %v", err)
+			}
+
+			backups, err := writer.backups()
+			if err != nil {
+				t.Fatalf("failed to list the backups: %v", err)
+			}
+
+			if len(backups) != test.wantBackups {
+				t.Errorf("expected %d backups, got %d", test.wantBackups, len(backups))
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	logFormatJSON    = "json"
+	logFormatConsole = "console"
+	logOutputStdout  = "stdout"
+	logOutputStderr  = "stderr"
+)
+
+func defaultLogFormat(config ConfigService) string {
+	if config.GetString("env") == "prod" {
+		return logFormatJSON
+	}
+
+	return logFormatConsole
+}
+
+// logSinks returns log.sinks, e.g. [{"output": "stdout", "format": "console"}, {"output": "/var/log/app.log"}],
+// or a single sink from log.output. A sink without a format uses log.format, which defaults to json in prod
+// and console elsewhere.
+func logSinks(config ConfigService) ([]logSinkConfig, error) {
+	settings, err := bindConfig[logConfig](config, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	format := lo.CoalesceOrEmpty(strings.ToLower(settings.Log.Format), defaultLogFormat(config))
+
+	sinks := settings.Log.Sinks
+	if len(sinks) == 0 {
+		sinks = []logSinkConfig{{Output: lo.CoalesceOrEmpty(settings.Log.Output, logOutputStdout)}}
+	}
+
+	return lo.Map(sinks, func(sink logSinkConfig, _ int) logSinkConfig {
+		sink.Format = lo.CoalesceOrEmpty(strings.ToLower(sink.Format), format)
+		return sink
+	}), nil
+}
+
+// LogSinkStats counts the events written and dropped by a sink, served by GET /admin/logSinks.
+type LogSinkStats struct {
+	Output  string `json:"output"`
+	Written uint64 `json:"written"`
+	Dropped uint64 `json:"dropped"`
+}
+
+type logSink struct {
+	output string
+	file   *rotatingFileWriter
+	async  *asyncWriter
+}
+
+func (s *logSink) stats() LogSinkStats {
+	return LogSinkStats{Output: s.output, Written: s.async.written.Load(), Dropped: s.async.dropped.Load()}
+}
+
+func (s *logSink) reopen() error {
+	return s.file.reopen()
+}
+
+func (s *logSink) close() error {
+	return errors.Join(s.async.Close(), s.file.Close())
+}
+
+func formatLogOutput(format string, out io.Writer, terminal bool) io.Writer {
+	if format == logFormatConsole {
+		return zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339, NoColor: !terminal}
+	}
+
+	return out
+}
+
+func newLogSinkWriter(sink logSinkConfig) (io.Writer, *logSink) {
+	switch strings.ToLower(sink.Output) {
+	case logOutputStdout:
+		return formatLogOutput(sink.Format, os.Stdout, true), nil
+	case logOutputStderr:
+		return formatLogOutput(sink.Format, os.Stderr, true), nil
+	}
+
+	file, err := newRotatingFileWriter(sink)
+	if err != nil {
+		panic(fmt.Sprintf(`Error opening log output %s. %s`, sink.Output, err))
+	}
+
+	fileSink := &logSink{
+		output: sink.Output,
+		file:   file,
+		async:  newAsyncWriter(formatLogOutput(sink.Format, file, false), sink.BufferSize),
+	}
+
+	return fileSink.async, fileSink
+}
+
+func newLogWriter(config ConfigService) (io.Writer, []*logSink, error) {
+	sinks, err := logSinks(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	writers := make([]io.Writer, 0)
+	fileSinks := make([]*logSink, 0)
+
+	for _, sink := range sinks {
+		writer, fileSink := newLogSinkWriter(sink)
+		writers = append(writers, writer)
+
+		if fileSink != nil {
+			fileSinks = append(fileSinks, fileSink)
+		}
+	}
+
+	if len(writers) == 1 {
+		return writers[0], fileSinks, nil
+	}
+
+	return zerolog.MultiLevelWriter(writers...), fileSinks, nil
+}
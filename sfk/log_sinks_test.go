@@ -0,0 +1,64 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"testing"
+)
+
+func TestLogSinks(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     map[string]any
+		wantOutput string
+		wantFormat string
+		wantErr    bool
+	}{
+		{name: "defaults", config: map[string]any{"env": "prod"}, wantOutput: logOutputStdout, wantFormat: logFormatJSON},
+		{name: "console outside prod", config: map[string]any{"env": "sandbox"}, wantOutput: logOutputStdout, wantFormat: logFormatConsole},
+		{
+			name:       "single output",
+			config:     map[string]any{"log": map[string]any{"output": "stderr", "format": "json"}},
+			wantOutput: logOutputStderr,
+			wantFormat: logFormatJSON,
+		},
+		{
+			name:       "sink format inherits log.format",
+			config:     map[string]any{"log": map[string]any{"format": "json", "sinks": []any{map[string]any{"output": "stderr"}}}},
+			wantOutput: logOutputStderr,
+			wantFormat: logFormatJSON,
+		},
+		{
+			name:    "invalid sink format",
+			config:  map[string]any{"log": map[string]any{"sinks": []any{map[string]any{"output": "stdout", "format": "xml"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "sink without output",
+			config:  map[string]any{"log": map[string]any{"sinks": []any{map[string]any{"format": "json"}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sinks, err := logSinks(NewAppWithOptions(&AppOptions{Config: test.config}).Config())
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an invalid sink config error, got %+v", sinks)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(sinks) != 1 || sinks[0].Output != test.wantOutput || sinks[0].Format != test.wantFormat {
+				t.Errorf("expected one %s sink in %s format, got %+v", test.wantOutput, test.wantFormat, sinks)
+			}
+		})
+	}
+}
@@ -3,35 +3,83 @@
 package sfk
 
 import (
+	"context"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"io"
 	"os"
-	"sync"
+	"strings"
 	"time"
 )
 
-var (
-	loggerServiceInstance *loggerService
-	loggerServiceOnce     sync.Once
-)
-
 type LoggerService interface {
 	ZeroLogger() *zerolog.Logger
+	// Component returns a logger tagging its events with component, leveled by log.levels[name] when set
+	// and logLevel otherwise. Both apply live on config reload.
+	Component(name string) LoggerService
+	Ctx(ctx context.Context) *zerolog.Logger
+	Trace(ginCtx *gin.Context) *zerolog.Event
+	Debug(ginCtx *gin.Context) *zerolog.Event
 	Info(ginCtx *gin.Context) *zerolog.Event
+	Warn(ginCtx *gin.Context) *zerolog.Event
 	Error(ginCtx *gin.Context) *zerolog.Event
 	Err(ginCtx *gin.Context, err error) *zerolog.Event
 	Fatal(ginCtx *gin.Context) *zerolog.Event
 	Panic(ginCtx *gin.Context) *zerolog.Event
+	// Levels reports the effective levels, served by GET /admin/logLevels.
+	Levels() LogLevels
+	// SetLevel overrides the level of component, or the global level when component is empty, for duration
+	// or until reset when it is 0. PUT /admin/logLevels takes {"component", "level", "duration"}.
+	SetLevel(component, level string, duration time.Duration) error
+	// ResetLevel reverts component to its configured level, as DELETE /admin/logLevels?component= does.
+	ResetLevel(component string) bool
+	SinkStats() []LogSinkStats
 }
 
 type loggerService struct {
 	*zerolog.Logger
+	output    io.Writer
+	sinks     []*logSink
+	levels    *logLevels
+	component string
+}
+
+type levelWriter struct {
+	writer    io.Writer
+	levels    *logLevels
+	component string
 }
 
-func getLogger() *zerolog.Logger {
-	environment := ConfigServiceInstance().GetString("env")
+func (w *levelWriter) Write(p []byte) (int, error) {
+	return w.writer.Write(p)
+}
+
+func (w *levelWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if !w.levels.enabled(w.component, level) {
+		return len(p), nil
+	}
+
+	if levelWriter, ok := w.writer.(zerolog.LevelWriter); ok {
+		return levelWriter.WriteLevel(level, p)
+	}
+
+	return w.writer.Write(p)
+}
+
+func parseLogLevel(value string) (zerolog.Level, error) {
+	if value == "" {
+		return zerolog.TraceLevel, nil
+	}
 
-	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}).
+	return zerolog.ParseLevel(value)
+}
+
+func getLogger(config ConfigService, writer io.Writer) *zerolog.Logger {
+	environment := config.GetString("env")
+
+	logger := zerolog.New(writer).
 		With().Str("env", environment).
 		Caller().
 		Timestamp().Logger()
@@ -39,14 +87,116 @@ func getLogger() *zerolog.Logger {
 	return &logger
 }
 
-func LoggerServiceInstance() LoggerService {
-	loggerServiceOnce.Do(func() {
-		loggerServiceInstance = &loggerService{
-			Logger: getLogger(),
+func newLoggerService(config ConfigService, writer io.Writer) *loggerService {
+	var sinks []*logSink
+	if writer == nil {
+		var err error
+		writer, sinks, err = newLogWriter(config)
+		if err != nil {
+			// the log config binding reports the invalid sinks when the config is validated
+			writer = formatLogOutput(defaultLogFormat(config), os.Stdout, true)
+		}
+	}
+
+	service := &loggerService{
+		output: writer,
+		sinks:  sinks,
+		levels: newLogLevels(),
+	}
+	service.Logger = getLogger(config, &levelWriter{writer: writer, levels: service.levels})
+	service.configureLevels(config)
+
+	onLevelsChange := func(_, _ any) {
+		if service.configureLevels(config) {
+			service.Logger.Info().Msgf("Log level changed to %s", service.levels.snapshot().Level)
 		}
+	}
+	config.OnChange("logLevel", onLevelsChange)
+	config.OnChange(logLevelsConfigKey, onLevelsChange)
+
+	return service
+}
+
+func (l *loggerService) configureLevels(config ConfigService) bool {
+	level, err := parseLogLevel(config.GetString("logLevel"))
+	if err == nil {
+		var components map[string]zerolog.Level
+		components, err = parseComponentLogLevels(config.GetStringMap(logLevelsConfigKey))
+
+		if err == nil {
+			l.levels.configure(level, components)
+			return true
+		}
+	}
+
+	l.Logger.Error().Err(err).Msgf("Invalid log level, keeping %s", l.levels.snapshot().Level)
+
+	return false
+}
+
+func (l *loggerService) Component(name string) LoggerService {
+	name = strings.ToLower(name)
+	logger := l.Logger.Output(&levelWriter{writer: l.output, levels: l.levels, component: name}).
+		With().Str("component", name).Logger()
+
+	return &loggerService{
+		Logger:    &logger,
+		output:    l.output,
+		sinks:     l.sinks,
+		levels:    l.levels,
+		component: name,
+	}
+}
+
+func (l *loggerService) Levels() LogLevels {
+	return l.levels.snapshot()
+}
+
+func (l *loggerService) SetLevel(component, level string, duration time.Duration) error {
+	parsed, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil || level == "" {
+		return fmt.Errorf(`invalid log level "%s", can be trace, debug, info, warn, error, fatal, panic or disabled`, level)
+	}
+
+	l.levels.override(strings.ToLower(component), parsed, duration)
+
+	return nil
+}
+
+func (l *loggerService) ResetLevel(component string) bool {
+	return l.levels.reset(strings.ToLower(component))
+}
+
+func (l *loggerService) SinkStats() []LogSinkStats {
+	return lo.Map(l.sinks, func(sink *logSink, _ int) LogSinkStats {
+		return sink.stats()
 	})
+}
 
-	return loggerServiceInstance
+func (l *loggerService) reopen() {
+	for _, sink := range l.sinks {
+		if err := sink.reopen(); err != nil {
+			l.Logger.Error().Err(err).Msgf("Failed to reopen log file %s", sink.output)
+		}
+	}
+}
+
+func (l *loggerService) close() {
+	for _, sink := range l.sinks {
+		if dropped := sink.stats().Dropped; dropped > 0 {
+			l.Logger.Warn().Msgf("Log sink %s dropped %d events", sink.output, dropped)
+		}
+	}
+
+	for _, sink := range l.sinks {
+		if err := sink.close(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to close log file %s: %s\n", sink.output, err)
+		}
+	}
+}
+
+func LoggerServiceInstance() LoggerService {
+	return currentApp().Logger()
 }
 
 func extractTraceId(ginCtx *gin.Context) (string, bool) {
@@ -62,59 +212,83 @@ func extractTraceId(ginCtx *gin.Context) (string, bool) {
 	return traceId, false
 }
 
-func (l *loggerService) Info(ginCtx *gin.Context) *zerolog.Event {
-	traceId, ok := extractTraceId(ginCtx)
-	if ok {
-		logger := l.Logger.With().Str("traceId", traceId).Logger()
+func (l *loggerService) Ctx(ctx context.Context) *zerolog.Logger {
+	logger, ok := loggerFromContext(ctx)
+	if !ok {
+		return l.Logger
+	}
 
-		return logger.Info()
+	if l.component == "" {
+		return logger
 	}
 
-	return l.Logger.Info()
+	componentLogger := logger.Output(&levelWriter{writer: l.output, levels: l.levels, component: l.component}).
+		With().Str("component", l.component).Logger()
+
+	return &componentLogger
 }
 
-func (l *loggerService) Error(ginCtx *gin.Context) *zerolog.Event {
+func (l *loggerService) withTraceId(ginCtx *gin.Context) *zerolog.Logger {
+	if _, found := loggerFromContext(ginCtx); found {
+		return l.Ctx(ginCtx)
+	}
+
 	traceId, ok := extractTraceId(ginCtx)
 	if ok {
 		logger := l.Logger.With().Str("traceId", traceId).Logger()
 
-		return logger.Error()
+		return &logger
 	}
 
-	return l.Logger.Error()
+	return l.Logger
 }
 
-func (l *loggerService) Err(ginCtx *gin.Context, err error) *zerolog.Event {
-	traceId, ok := extractTraceId(ginCtx)
-	if ok {
-		logger := l.Logger.With().Str("traceId", traceId).Logger()
+func (l *loggerService) enabled(level zerolog.Level) bool {
+	return l.levels.enabled(l.component, level)
+}
 
-		return logger.Err(err)
+func (l *loggerService) event(ginCtx *gin.Context, level zerolog.Level) *zerolog.Event {
+	if !l.enabled(level) {
+		return nil
 	}
 
-	return l.Logger.Err(err)
+	return l.withTraceId(ginCtx).WithLevel(level)
 }
 
-func (l *loggerService) Fatal(ginCtx *gin.Context) *zerolog.Event {
-	traceId, ok := extractTraceId(ginCtx)
-	if ok {
-		logger := l.Logger.With().Str("traceId", traceId).Logger()
+func (l *loggerService) Trace(ginCtx *gin.Context) *zerolog.Event {
+	return l.event(ginCtx, zerolog.TraceLevel)
+}
 
-		return logger.Fatal()
-	}
+func (l *loggerService) Debug(ginCtx *gin.Context) *zerolog.Event {
+	return l.event(ginCtx, zerolog.DebugLevel)
+}
 
-	return l.Logger.Fatal()
+func (l *loggerService) Info(ginCtx *gin.Context) *zerolog.Event {
+	return l.event(ginCtx, zerolog.InfoLevel)
 }
 
-func (l *loggerService) Panic(ginCtx *gin.Context) *zerolog.Event {
-	traceId, ok := extractTraceId(ginCtx)
-	if ok {
-		logger := l.Logger.With().Str("traceId", traceId).Logger()
+func (l *loggerService) Warn(ginCtx *gin.Context) *zerolog.Event {
+	return l.event(ginCtx, zerolog.WarnLevel)
+}
+
+func (l *loggerService) Error(ginCtx *gin.Context) *zerolog.Event {
+	return l.event(ginCtx, zerolog.ErrorLevel)
+}
 
-		return logger.Panic()
+func (l *loggerService) Err(ginCtx *gin.Context, err error) *zerolog.Event {
+	if err == nil {
+		return l.Info(ginCtx)
 	}
 
-	return l.Logger.Panic()
+	return l.Error(ginCtx).Err(err)
+}
+
+func (l *loggerService) Fatal(ginCtx *gin.Context) *zerolog.Event {
+	return l.withTraceId(ginCtx).Fatal()
+}
+
+func (l *loggerService) Panic(ginCtx *gin.Context) *zerolog.Event {
+	return l.withTraceId(ginCtx).Panic()
 }
 
 func (l *loggerService) ZeroLogger() *zerolog.Logger {
@@ -23,14 +23,15 @@ type middlewareOptions struct {
 }
 
 type middlewareService struct {
+	app     *App
 	router  *gin.Engine
 	options *middlewareOptions
 }
 
-func newMiddlewareService(options *middlewareOptions) MiddlewareService {
-	routerInstance := RouterInstance()
+func newMiddlewareService(app *App, options *middlewareOptions) MiddlewareService {
 	return &middlewareService{
-		router:  routerInstance.Router(),
+		app:     app,
+		router:  app.Router().Router(),
 		options: options,
 	}
 }
@@ -44,8 +45,10 @@ func applyCors() gin.HandlerFunc {
 }
 
 func (m *middlewareService) registerMiddlewares(middlewares ...gin.HandlerFunc) {
+	m.router.Use(applyApp(m.app))
+
 	if !m.options.skipRateLimiterMiddleware {
-		m.router.Use(applyRateLimiter())
+		m.router.Use(applyRateLimiter(m.app.Config(), m.app.Logger()))
 	}
 
 	if !m.options.skipRequestTimeoutMiddleware {
@@ -57,7 +60,7 @@ func (m *middlewareService) registerMiddlewares(middlewares ...gin.HandlerFunc)
 	}
 
 	if !m.options.skipRequestLoggerMiddleware {
-		m.router.Use(applyRequestLoggerMiddleware())
+		m.router.Use(applyRequestLoggerMiddleware(m.app.Logger()))
 	}
 
 	if !m.options.overrideCorsMiddleware {
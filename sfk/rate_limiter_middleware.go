@@ -4,25 +4,44 @@ package sfk
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"go.uber.org/ratelimit"
+	"sync/atomic"
 )
 
 type rateLimiterMiddleware struct {
-	limiter ratelimit.Limiter
+	limiter atomic.Pointer[ratelimit.Limiter]
 }
 
 func (rl *rateLimiterMiddleware) applyFilter() gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
-		rl.limiter.Take()
+		(*rl.limiter.Load()).Take()
 
 		ginCtx.Next()
 	}
 }
 
-func applyRateLimiter() gin.HandlerFunc {
-	config := ConfigServiceInstance()
-	limiter := ratelimit.New(config.GetInt("rateLimitCallsPerSec"))
-	instance := &rateLimiterMiddleware{limiter}
+func (rl *rateLimiterMiddleware) update(config ConfigService, logger *zerolog.Logger) {
+	rateLimiter, err := BindConfig[rateLimiterConfig](config, "")
+	if err != nil {
+		logger.Error().Msgf("Rate limiter not updated: %s", err)
+		return
+	}
+
+	limiter := ratelimit.New(rateLimiter.RateLimitCallsPerSec)
+	rl.limiter.Store(&limiter)
+
+	logger.Info().Msgf("Rate limiter set to %d calls per second", rateLimiter.RateLimitCallsPerSec)
+}
+
+func applyRateLimiter(config ConfigService, logger LoggerService) gin.HandlerFunc {
+	limiter := ratelimit.New(MustBindConfig[rateLimiterConfig](config, "").RateLimitCallsPerSec)
+	instance := &rateLimiterMiddleware{}
+	instance.limiter.Store(&limiter)
+
+	config.OnChange("rateLimitCallsPerSec", func(_, _ any) {
+		instance.update(config, logger.ZeroLogger())
+	})
 
 	return instance.applyFilter()
 }
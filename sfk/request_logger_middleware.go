@@ -68,6 +68,6 @@ func (r *requestLoggerMiddleware) applyFilter() gin.HandlerFunc {
 	}
 }
 
-func applyRequestLoggerMiddleware() gin.HandlerFunc {
-	return (&requestLoggerMiddleware{logger: LoggerServiceInstance()}).applyFilter()
+func applyRequestLoggerMiddleware(logger LoggerService) gin.HandlerFunc {
+	return (&requestLoggerMiddleware{logger: logger}).applyFilter()
 }
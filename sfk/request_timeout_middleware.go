@@ -3,17 +3,34 @@
 package sfk
 
 import (
+	"context"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/omkarsrepo/server-framework/sfk/boom"
-	"golang.org/x/net/context"
 	"net/http"
+	"strings"
 	"time"
 )
 
+const defaultRequestTimeout = 20 * time.Second
+
+func requestTimeout(config ConfigService) time.Duration {
+	timeout := config.GetDurationOrDefault("requestTimeout", time.Duration(config.GetInt("requestTimeoutSecs"))*time.Second)
+	if timeout <= 0 {
+		return defaultRequestTimeout
+	}
+
+	return timeout
+}
+
 func ApplyRequestTimeout() gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		if strings.HasPrefix(ginCtx.FullPath(), pprofPrefix+"/pprof/") {
+			ginCtx.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), requestTimeout(appFromContext(ginCtx).Config()))
 		defer cancel()
 
 		ginCtx.Request = ginCtx.Request.WithContext(ctx)
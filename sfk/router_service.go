@@ -5,12 +5,6 @@ package sfk
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"sync"
-)
-
-var (
-	routerServiceInstance *routerService
-	routerServiceOnce     sync.Once
 )
 
 type RouterService interface {
@@ -27,8 +21,7 @@ func registerHealthPingEndpoint(router *gin.Engine) {
 	})
 }
 
-func getRouter() *gin.Engine {
-	config := ConfigServiceInstance()
+func getRouter(config ConfigService) *gin.Engine {
 	router := gin.Default()
 
 	if config.GetString("env") != "prod" {
@@ -42,14 +35,14 @@ func getRouter() *gin.Engine {
 	return router
 }
 
-func RouterInstance() RouterService {
-	routerServiceOnce.Do(func() {
-		routerServiceInstance = &routerService{
-			Engine: getRouter(),
-		}
-	})
+func newRouterService(config ConfigService) RouterService {
+	return &routerService{
+		Engine: getRouter(config),
+	}
+}
 
-	return routerServiceInstance
+func RouterInstance() RouterService {
+	return currentApp().Router()
 }
 
 func (r *routerService) Router() *gin.Engine {
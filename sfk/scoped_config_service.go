@@ -0,0 +1,114 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+	"strings"
+	"time"
+)
+
+type scopedConfigService struct {
+	parent ConfigService
+	prefix string
+}
+
+func newScopedConfigService(parent ConfigService, prefix string) ConfigService {
+	return &scopedConfigService{
+		parent: parent,
+		prefix: strings.Trim(prefix, "."),
+	}
+}
+
+func (s *scopedConfigService) key(key string) string {
+	if key == "" {
+		return s.prefix
+	}
+
+	return s.prefix + "." + key
+}
+
+func (s *scopedConfigService) GetString(key string) string {
+	return s.parent.GetString(s.key(key))
+}
+
+func (s *scopedConfigService) GetInt(key string) int {
+	return s.parent.GetInt(s.key(key))
+}
+
+func (s *scopedConfigService) GetInt64(key string) int64 {
+	return s.parent.GetInt64(s.key(key))
+}
+
+func (s *scopedConfigService) GetBool(key string) bool {
+	return s.parent.GetBool(s.key(key))
+}
+
+func (s *scopedConfigService) GetFloat64(key string) float64 {
+	return s.parent.GetFloat64(s.key(key))
+}
+
+func (s *scopedConfigService) GetDuration(key string) time.Duration {
+	return s.parent.GetDuration(s.key(key))
+}
+
+func (s *scopedConfigService) GetStringSlice(key string) []string {
+	return s.parent.GetStringSlice(s.key(key))
+}
+
+func (s *scopedConfigService) GetStringMap(key string) map[string]any {
+	return s.parent.GetStringMap(s.key(key))
+}
+
+func (s *scopedConfigService) GetStringOrDefault(key string, defaultValue string) string {
+	return s.parent.GetStringOrDefault(s.key(key), defaultValue)
+}
+
+func (s *scopedConfigService) GetIntOrDefault(key string, defaultValue int) int {
+	return s.parent.GetIntOrDefault(s.key(key), defaultValue)
+}
+
+func (s *scopedConfigService) GetInt64OrDefault(key string, defaultValue int64) int64 {
+	return s.parent.GetInt64OrDefault(s.key(key), defaultValue)
+}
+
+func (s *scopedConfigService) GetBoolOrDefault(key string, defaultValue bool) bool {
+	return s.parent.GetBoolOrDefault(s.key(key), defaultValue)
+}
+
+func (s *scopedConfigService) GetFloat64OrDefault(key string, defaultValue float64) float64 {
+	return s.parent.GetFloat64OrDefault(s.key(key), defaultValue)
+}
+
+func (s *scopedConfigService) GetDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	return s.parent.GetDurationOrDefault(s.key(key), defaultValue)
+}
+
+func (s *scopedConfigService) GetStringSliceOrDefault(key string, defaultValue []string) []string {
+	return s.parent.GetStringSliceOrDefault(s.key(key), defaultValue)
+}
+
+func (s *scopedConfigService) IsSet(key string) bool {
+	return s.parent.IsSet(s.key(key))
+}
+
+func (s *scopedConfigService) Sub(prefix string) ConfigService {
+	return newScopedConfigService(s.parent, s.key(strings.Trim(prefix, ".")))
+}
+
+func (s *scopedConfigService) GetViper() *viper.Viper {
+	scoped := viper.New()
+	_ = scoped.MergeConfigMap(cast.ToStringMap(configSection(s.parent.GetViper().AllSettings(), s.prefix)))
+
+	return scoped
+}
+
+func (s *scopedConfigService) OnChange(key string, handler func(oldValue, newValue any)) {
+	s.parent.OnChange(s.key(key), handler)
+}
+
+func (s *scopedConfigService) decodeHook(resolveSecrets bool) mapstructure.DecodeHookFunc {
+	return decodeHookFor(s.parent, resolveSecrets)
+}
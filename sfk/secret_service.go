@@ -12,15 +12,13 @@ import (
 	"github.com/omkarsrepo/server-framework/sfk/password"
 	"github.com/rs/zerolog"
 	"os"
-	"sync"
 	"time"
 )
 
-const secretTokenCacheKey = "FetchSecretToken"
-
-var (
-	singletonSecretService *secretService
-	once                   sync.Once
+const (
+	secretTokenCacheKey    = "FetchSecretToken"
+	defaultSecretsCacheTTL = 2 * time.Hour
+	defaultSecretsTokenTTL = 59 * time.Minute
 )
 
 type SecretService interface {
@@ -30,6 +28,13 @@ type SecretService interface {
 	Delete(secretName string) boom.Exception
 }
 
+// SecretResolver is implemented by secret services that can look a secret up by its name instead of
+// by the config key holding the name. The built-in SecretService implements it, and secret references
+// in config (secret://<name>, ${secret:<name>}) only resolve through a SecretService that does.
+type SecretResolver interface {
+	Resolve(secretName string) (string, boom.Exception)
+}
+
 type secretService struct {
 	secretTokenCache otter.Cache[string, any]
 	secretCache      otter.CacheWithVariableTTL[string, any]
@@ -38,32 +43,35 @@ type secretService struct {
 	logger           *zerolog.Logger
 }
 
-func SecretServiceInstance() SecretService {
-	once.Do(func() {
-		cache := Cache()
-		restyClient := resty.New().
-			SetJSONMarshaler(jsoniter.ConfigCompatibleWithStandardLibrary.Marshal).
-			SetJSONUnmarshaler(jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal)
-
-		secretTokenCache := cache.New(1, time.Minute*59)
-		secretCache := cache.NewVariable(20)
-
-		loggerInstance := LoggerServiceInstance()
-
-		singletonSecretService = &secretService{
-			secretTokenCache: secretTokenCache,
-			secretCache:      secretCache,
-			restyClient:      restyClient,
-			config:           ConfigServiceInstance(),
-			logger:           loggerInstance.ZeroLogger(),
-		}
-	})
+func newSecretService(config ConfigService, logger LoggerService, cache CacheService) SecretService {
+	restyClient := resty.New().
+		SetJSONMarshaler(jsoniter.ConfigCompatibleWithStandardLibrary.Marshal).
+		SetJSONUnmarshaler(jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal).
+		SetLogger(NewRestyLogger(logger.Component("resty")))
+
+	return &secretService{
+		secretTokenCache: cache.New(1, rawDurationOrDefault(config, "secrets.tokenTTL", defaultSecretsTokenTTL)),
+		secretCache:      cache.NewVariable(20),
+		restyClient:      restyClient,
+		config:           config,
+		logger:           logger.ZeroLogger(),
+	}
+}
+
+func rawDurationOrDefault(config ConfigService, key string, defaultValue time.Duration) time.Duration {
+	if duration, valid := configDuration(config.GetViper().Get(key)); config.GetViper().IsSet(key) && valid {
+		return duration
+	}
 
-	return singletonSecretService
+	return defaultValue
+}
+
+func SecretServiceInstance() SecretService {
+	return currentApp().Secrets()
 }
 
 func (s *secretService) setVariableCache(name string, secret any) {
-	s.secretCache.Set(name, secret, time.Hour*2)
+	s.secretCache.Set(name, secret, rawDurationOrDefault(s.config, "secrets.cacheTTL", defaultSecretsCacheTTL))
 }
 
 func (s *secretService) variableCache(name string) (any, bool) {
@@ -74,10 +82,25 @@ func (s *secretService) deleteVariableCache(name string) {
 	s.secretCache.Delete(name)
 }
 
+func (s *secretService) hashicorpConfig() (hashicorpConfig, boom.Exception) {
+	settings, err := bindConfig[hashicorpConfig](s.config, "", false)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Secret service is not configured")
+		return settings, boom.InternalServerError()
+	}
+
+	return settings, nil
+}
+
 func (s *secretService) fetchSecretToken() (string, boom.Exception) {
+	settings, exp := s.hashicorpConfig()
+	if exp != nil {
+		return "", exp
+	}
+
 	expectedBody := map[string]string{
 		"grant_type":    "client_credentials",
-		"client_id":     s.config.GetString("clientIds.hashicorp"),
+		"client_id":     settings.ClientIds.Hashicorp,
 		"client_secret": os.Getenv("hashicorpSecret"),
 		"audience":      "https://api.hashicorp.cloud",
 	}
@@ -119,9 +142,14 @@ func (s *secretService) getSecretToken() (string, boom.Exception) {
 }
 
 func (s *secretService) fetchSecret(secretName string) (string, boom.Exception) {
-	organizationId := s.config.GetString("hashicorp.organizationId")
-	projectId := s.config.GetString("hashicorp.projectId")
-	env := s.config.GetString("env")
+	settings, exp := s.hashicorpConfig()
+	if exp != nil {
+		return "", exp
+	}
+
+	organizationId := settings.Hashicorp.OrganizationId
+	projectId := settings.Hashicorp.ProjectId
+	env := s.config.GetViper().GetString("env")
 
 	secretToken, exp := s.getSecretToken()
 	if exp != nil {
@@ -145,7 +173,7 @@ func (s *secretService) fetchSecret(secretName string) (string, boom.Exception)
 	val, err := json.ValueOf[string](responseResult, "secret.version.value")
 	if err != nil {
 		s.logger.Error().Err(err).
-			Msgf("Failed to destructure value 'secret.version.value' for result: %+v", responseResult)
+			Msgf("Failed to destructure value 'secret.version.value' for secret %s", secretName)
 		return "", boom.InternalServerError()
 	}
 
@@ -153,8 +181,10 @@ func (s *secretService) fetchSecret(secretName string) (string, boom.Exception)
 }
 
 func (s *secretService) ValueOf(secretKey string) (string, boom.Exception) {
-	secretName := s.config.GetString(secretKey)
+	return s.Resolve(s.config.GetViper().GetString(secretKey))
+}
 
+func (s *secretService) Resolve(secretName string) (string, boom.Exception) {
 	secret, ok := s.variableCache(secretName)
 	if !ok {
 		secret, exp := s.fetchSecret(secretName)
@@ -170,6 +200,20 @@ func (s *secretService) ValueOf(secretKey string) (string, boom.Exception) {
 	return secret.(string), nil
 }
 
+// freshValueOf fetches the secret named by secretKey past the cache and caches the fetched value.
+func (s *secretService) freshValueOf(secretKey string) (string, boom.Exception) {
+	secretName := s.config.GetViper().GetString(secretKey)
+
+	secret, exp := s.fetchSecret(secretName)
+	if exp != nil {
+		return "", exp
+	}
+
+	s.setVariableCache(secretName, secret)
+
+	return secret, nil
+}
+
 func (s *secretService) PurgeSecretsCache() {
 	s.secretCache.Clear()
 }
@@ -181,9 +225,14 @@ func (s *secretService) Create(secretName string, value ...string) (string, boom
 		secretValue = value[0]
 	}
 
-	organizationId := s.config.GetString("hashicorp.organizationId")
-	projectId := s.config.GetString("hashicorp.projectId")
-	env := s.config.GetString("env")
+	settings, exp := s.hashicorpConfig()
+	if exp != nil {
+		return "", exp
+	}
+
+	organizationId := settings.Hashicorp.OrganizationId
+	projectId := settings.Hashicorp.ProjectId
+	env := s.config.GetViper().GetString("env")
 
 	secretToken, exp := s.getSecretToken()
 	if exp != nil {
@@ -213,9 +262,14 @@ func (s *secretService) Create(secretName string, value ...string) (string, boom
 }
 
 func (s *secretService) Delete(secretName string) boom.Exception {
-	organizationId := s.config.GetString("hashicorp.organizationId")
-	projectId := s.config.GetString("hashicorp.projectId")
-	env := s.config.GetString("env")
+	settings, exp := s.hashicorpConfig()
+	if exp != nil {
+		return exp
+	}
+
+	organizationId := settings.Hashicorp.OrganizationId
+	projectId := settings.Hashicorp.ProjectId
+	env := s.config.GetViper().GetString("env")
 
 	secretToken, exp := s.getSecretToken()
 	if exp != nil {
@@ -5,32 +5,43 @@ package sfk
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
-	"github.com/omkarsrepo/server-framework/sfk/boom"
-	"github.com/omkarsrepo/server-framework/sfk/json"
 	"github.com/omkarsrepo/server-framework/sfk/types"
 	"github.com/rs/zerolog"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	_ "go.uber.org/automaxprocs"
+	"math"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"runtime/debug"
+	"sync"
 	"syscall"
 	"time"
 )
 
+const pprofPrefix = "/metrics"
+
 type ServerService interface {
 	Start()
+	Handler() (http.Handler, error)
+	Stop(ctx context.Context) error
+	App() *App
+	Lifecycle() LifecycleService
 }
 
 type serverService struct {
+	app                            *App
 	cmd                            *cobra.Command
 	logger                         *zerolog.Logger
 	config                         ConfigService
 	router                         *gin.Engine
 	cleanup                        func()
+	lifecycleHooks                 []types.LifecycleHook
 	shouldOverrideCors             bool
 	middlewares                    []gin.HandlerFunc
 	routes                         func()
@@ -42,28 +53,46 @@ type serverService struct {
 	skipTraceHeaderMiddleware      bool
 	skipRequestLoggerMiddleware    bool
 	disablePprof                   bool
+	tlsOptions                     *types.TLSOptions
+	httpServerOptions              *types.HTTPServerOptions
+	httpServer                     HTTPServerService
+	listenAddresses                []string
+	enableH2C                      bool
+	enableHTTP3                    bool
+	protocols                      HTTPProtocolsService
+	tls                            TLSService
+	enableAdminServer              bool
+	admin                          AdminService
+	restart                        GracefulRestartService
+	configReload                   ConfigReloadService
+	logReopen                      LogReopenService
+	healthChecks                   []types.HealthCheck
+	initializeOnce                 sync.Once
+	initializeErr                  error
 }
 
 func NewServerService(name, description string, options *types.ServerOptions) ServerService {
+	return NewServerServiceWithApp(claimApp(), name, description, options)
+}
+
+func NewServerServiceWithApp(app *App, name, description string, options *types.ServerOptions) ServerService {
 	cobraCmd := &cobra.Command{
 		Use:   name,
 		Short: description,
 	}
 
-	commandsService := newCommandsService(cobraCmd)
-	commandsService.registerCommands()
+	app.addConfigSources(options.ConfigSources...)
 
-	routerInstance := RouterInstance()
-	loggerInstance := LoggerServiceInstance()
+	commandsService := newCommandsService(cobraCmd, app, options.EnvPrefix)
+	commandsService.registerCommands()
 
-	return &serverService{
+	server := &serverService{
+		app:                            app,
 		cmd:                            cobraCmd,
-		logger:                         loggerInstance.ZeroLogger(),
-		config:                         ConfigServiceInstance(),
-		router:                         routerInstance.Router(),
 		shouldOverrideCors:             options.ShouldOverrideCORSMiddleware,
 		middlewares:                    options.Middlewares,
 		cleanup:                        options.ShutdownHook,
+		lifecycleHooks:                 options.LifecycleHooks,
 		routes:                         options.Routes,
 		database:                       options.Database,
 		disableGzipCompression:         options.ShouldDisableGzipCompression,
@@ -73,59 +102,228 @@ func NewServerService(name, description string, options *types.ServerOptions) Se
 		skipTraceHeaderMiddleware:      options.SkipTraceHeaderMiddleware,
 		skipRequestLoggerMiddleware:    options.SkipRequestLoggerMiddleware,
 		disablePprof:                   options.DisablePprof,
+		tlsOptions:                     options.TLS,
+		httpServerOptions:              options.HTTPServer,
+		listenAddresses:                options.ListenAddresses,
+		enableH2C:                      options.EnableH2C,
+		enableHTTP3:                    options.EnableHTTP3,
+		enableAdminServer:              options.EnableAdminServer,
+		healthChecks:                   options.HealthChecks,
 	}
+
+	cobraCmd.AddCommand(newConfigCommand(app, server.registerCommandBindings))
+
+	return server
 }
 
-func (s *serverService) cleanupOnShutdown() {
-	go func() {
-		Cache().Close()
+func (s *serverService) registerConfigBindings() {
+	registerFrameworkConfig(s.app, !s.skipRateLimiterMiddleware)
+}
+
+// registerCommandBindings registers the config bindings and keys the server would on Start, the framework
+// ones and those added by RegisterConfig calls in ServerOptions.Routes, for the config subcommands. It
+// opens no listeners and skips the Database callback and the start hooks.
+func (s *serverService) registerCommandBindings() error {
+	s.resolveServices()
+	s.registerConfigBindings()
+
+	if err := s.app.ValidateConfig(); err != nil {
+		return err
+	}
+
+	s.app.activate(func() {
+		s.initializeServer(s.routes, nil)
+	})
+
+	return nil
+}
+
+func (s *serverService) resolveServices() {
+	s.config = s.app.Config()
+	s.logger = s.app.Logger().ZeroLogger()
+	s.router = s.app.Router().Router()
+	s.tls = newTLSService(s.app, s.tlsOptions)
+	s.admin = newAdminService(s.app, s.enableAdminServer)
+	s.httpServer = newHTTPServerService(s.app, s.httpServerOptions, !s.disablePprof && !s.admin.enabled())
+	s.protocols = newHTTPProtocolsService(s.app, s.enableH2C, s.enableHTTP3)
+	s.restart = newGracefulRestartService(s.config, s.app.Logger())
+	s.configReload = newConfigReloadService(s.app, s.restart)
+	s.logReopen = newLogReopenService(s.app, s.restart, s.configReload)
+}
+
+func (s *serverService) registerLifecycleHooks() {
+	lifecycle := s.app.Lifecycle()
+
+	lifecycle.Append(types.LifecycleHook{
+		Name:     "configReload",
+		Priority: math.MinInt,
+		OnStart: func(context.Context) error {
+			return s.configReload.start()
+		},
+		OnStop: func(context.Context) error {
+			s.configReload.close()
+			return nil
+		},
+	})
+
+	lifecycle.Append(types.LifecycleHook{
+		Name:     "logReopen",
+		Priority: math.MinInt,
+		OnStart: func(context.Context) error {
+			s.logReopen.start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			s.logReopen.close()
+			return nil
+		},
+	})
 
-		if s.cleanup != nil {
+	lifecycle.OnStop("cache", math.MinInt, func(context.Context) error {
+		s.app.Cache().Close()
+		return nil
+	})
+
+	if s.cleanup != nil {
+		lifecycle.OnStop("shutdownHook", 0, func(context.Context) error {
 			s.cleanup()
-		}
-	}()
+			return nil
+		})
+	}
+
+	lifecycle.Append(s.lifecycleHooks...)
+}
+
+func lifecycleTimeout(config ConfigService, key string) time.Duration {
+	timeoutSecs := config.GetInt(key)
+	if timeoutSecs <= 0 {
+		timeoutSecs = 30
+	}
+
+	return time.Duration(timeoutSecs) * time.Second
+}
+
+func (s *serverService) stopLifecycle() {
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout(s.config, "lifecycle.stopTimeoutSecs"))
+	defer cancel()
+
+	if err := s.app.lifecycleService().stop(ctx); err != nil {
+		s.logger.Error().Msgf("Lifecycle stop hooks completed with errors: %+v", err)
+	}
+}
+
+func (s *serverService) runStartHooks() error {
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout(s.config, "lifecycle.startTimeoutSecs"))
+	defer cancel()
+
+	if err := s.app.lifecycleService().start(ctx); err != nil {
+		s.stopLifecycle()
+		return err
+	}
+
+	return nil
+}
+
+func (s *serverService) installLogBridge() {
+	if s.app != defaultApp || !s.config.GetBoolOrDefault("log.bridge", true) {
+		return
+	}
+
+	installLogBridge(s.app.Logger())
+}
+
+func (s *serverService) validateConfig() error {
+	if err := s.app.ValidateConfig(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return nil
 }
 
 func (s *serverService) setMaxMemoryLimit() {
 	if s.config.GetString("env") != "localhost" {
-		maxMemoryLimit := s.config.GetInt64("maxMemoryLimitInMB")
+		maxMemoryLimit := MustBindConfig[memoryLimitConfig](s.config, "").MaxMemoryLimitInMB
 
-		debug.SetMemoryLimit(maxMemoryLimit * 1 << 20)
+		if maxMemoryLimit > 0 {
+			debug.SetMemoryLimit(maxMemoryLimit * 1 << 20)
+		}
 	}
 }
 
-func (s *serverService) shutdownGracefully(server *http.Server) {
+func (s *serverService) awaitShutdownSignal() {
 	quit := make(chan os.Signal, 1)
 	defer close(quit)
 
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	signals := []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	restartSignal, restartEnabled := s.restart.restartSignal()
+	if restartEnabled {
+		signals = append(signals, restartSignal)
+	}
+
+	signal.Notify(quit, signals...)
+	defer signal.Stop(quit)
+
+	for received := range quit {
+		if !restartEnabled || received != restartSignal {
+			return
+		}
+
+		s.logger.Info().Msgf("Received %s, starting graceful restart...", received)
 
-	<-quit
+		if err := s.restart.restart(); err != nil {
+			s.logger.Error().Err(err).Msg("Graceful restart failed, continuing to serve")
+			continue
+		}
+
+		return
+	}
+}
+
+func (s *serverService) shutdownGracefully(server *http.Server) {
+	s.awaitShutdownSignal()
 
 	s.logger.Info().Msg("Received shutdown server event...")
+	s.app.healthRegistry().markDraining()
+
+	if drainDelaySecs := s.config.GetInt("health.drainDelaySecs"); drainDelaySecs > 0 {
+		s.logger.Info().Msgf("Readiness marked as draining, waiting %d seconds before closing listeners...", drainDelaySecs)
+		time.Sleep(time.Duration(drainDelaySecs) * time.Second)
+	}
 
-	gracefulShutdownSecs := s.config.GetInt("gracefulShutdownSecs")
-	gracefulShutdown := time.Duration(gracefulShutdownSecs) * time.Second
+	gracefulShutdown := s.config.GetDuration("gracefulShutdown")
+	if gracefulShutdown <= 0 {
+		gracefulShutdown = time.Duration(s.config.GetInt("gracefulShutdownSecs")) * time.Second
+	}
 
-	s.logger.Info().Msgf("Server Shutdown timeout of %s seconds...", gracefulShutdown)
+	s.logger.Info().Msgf("Server Shutdown timeout of %s...", gracefulShutdown)
 
+	startedAt := time.Now()
 	ctx, cancel := context.WithTimeout(context.Background(), gracefulShutdown)
 	defer cancel()
 
-	s.cleanupOnShutdown()
-
 	if err := server.Shutdown(ctx); err != nil {
-		s.logger.Fatal().Msgf("Server failed to gracefully shutdown before timeout: %+v", err)
+		s.logger.Error().Msgf("Server failed to gracefully shutdown before timeout: %+v", err)
 	}
 
-	<-ctx.Done()
-	s.logger.Info().Msgf("Server Shutdown timeout of %s seconds completed successfully. Server Exited!", gracefulShutdown)
+	if err := s.protocols.shutdown(ctx); err != nil {
+		s.logger.Error().Msgf("HTTP/3 server failed to gracefully shutdown before timeout: %+v", err)
+	}
+
+	if err := s.admin.shutdown(ctx); err != nil {
+		s.logger.Error().Msgf("Admin server failed to gracefully shutdown before timeout: %+v", err)
+	}
+
+	s.stopLifecycle()
+	s.tls.close()
+
+	s.logger.Info().Msgf("Server Shutdown completed in %s. Server Exited!", time.Since(startedAt).Round(time.Millisecond))
+	s.app.loggerService().close()
 }
 
 func (s *serverService) initializeServer(routes func(), database func()) {
 	s.setMaxMemoryLimit()
 
-	middlewareService := newMiddlewareService(&middlewareOptions{
+	middlewareService := newMiddlewareService(s.app, &middlewareOptions{
 		overrideCorsMiddleware:         s.shouldOverrideCors,
 		disableGzipCompression:         s.disableGzipCompression,
 		excludePathsForGzipCompression: s.excludePathsForGzipCompression,
@@ -140,6 +338,12 @@ func (s *serverService) initializeServer(routes func(), database func()) {
 	customValidators := newCustomValidatorsService()
 	customValidators.registerCustomValidators()
 
+	s.app.Health().Register(s.healthChecks...)
+	if !s.admin.enabled() || s.config.GetBool("health.public") {
+		registerHealthEndpoints(s.router, s.app.Health())
+	}
+	s.registerLifecycleHooks()
+
 	if routes != nil {
 		routes()
 	}
@@ -149,58 +353,154 @@ func (s *serverService) initializeServer(routes func(), database func()) {
 	}
 }
 
-func enablePprof(router *gin.Engine) {
-	secretService := SecretServiceInstance()
+func registerPprof(router *gin.Engine, secretService SecretService) {
+	pprofEndpoint := router.Group(pprofPrefix, authorizeWithSecret(secretService, "pprofSecret"))
 
-	pprofEndpoint := router.Group("/metrics", func(ginCtx *gin.Context) {
-		authToken, exp := json.ExtractAuthorization(ginCtx)
-		if exp != nil {
-			Abort(ginCtx, exp)
-			return
-		}
+	pprof.RouteRegister(pprofEndpoint, "pprof")
+}
 
-		val, exp := secretService.ValueOf("pprofSecret")
-		if exp != nil {
-			Abort(ginCtx, exp)
-			return
-		}
+func (s *serverService) serve(server *http.Server, listener net.Listener, useTLS bool) error {
+	if useTLS {
+		return server.ServeTLS(listener, "", "")
+	}
 
-		if authToken != val {
-			Abort(ginCtx, boom.Unauthorized("Invalid authToken for authorization header"))
-			return
-		}
+	return server.Serve(listener)
+}
 
-		ginCtx.Next()
-	})
+func (s *serverService) resolveListenAddresses() []listenAddress {
+	specs := s.listenAddresses
+	if len(specs) == 0 {
+		specs = s.config.GetStringSlice("listen")
+	}
 
-	pprof.RouteRegister(pprofEndpoint, "pprof")
+	if len(specs) == 0 {
+		specs = []string{":" + s.config.GetString("port")}
+	}
+
+	addresses, err := parseListenAddresses(specs)
+	if err != nil {
+		s.logger.Fatal().Msgf("Invalid listen addresses... %+v", err)
+	}
+
+	return addresses
 }
 
-func (s *serverService) startServer() {
-	if !s.disablePprof {
-		enablePprof(s.router)
+func (s *serverService) listenAndServe(server *http.Server, address listenAddress) {
+	listener, err := s.restart.listen(address.network, address.address, true)
+	if err != nil {
+		s.logger.Fatal().Msgf("Failed to start server... listen: %+v\n", err)
 	}
 
-	port := s.config.GetString("port")
-	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: s.router.Handler(),
+	if address.network == "unix" {
+		if err := os.Chmod(address.address, address.mode); err != nil {
+			s.logger.Fatal().Msgf("Failed to set permissions on unix socket %s: %+v", address.address, err)
+		}
 	}
 
+	useTLS := s.tls.enabled() && address.network == "tcp"
+
 	go func() {
-		s.logger.Info().Msgf("Server running successfully on port %s", port)
+		s.logger.Info().Msgf("Server running successfully on %s with TLS %t", address, useTLS)
 
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := s.serve(server, s.httpServer.wrapListener(listener), useTLS); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.logger.Fatal().Msgf("Failed to start server... listen: %+v\n", err)
 		}
 	}()
+}
 
+func (s *serverService) startServer() {
+	if s.admin.enabled() {
+		s.admin.registerRoutes(!s.disablePprof)
+		s.admin.start(s.restart, s.httpServer)
+	} else if !s.disablePprof {
+		registerPprof(s.router, s.app.Secrets())
+	}
+
+	addresses := s.resolveListenAddresses()
+	tcpAddress, _ := lo.Find(addresses, func(address listenAddress) bool {
+		return address.network == "tcp"
+	})
+
+	server := s.httpServer.newServer(tcpAddress.address, s.router.Handler())
+
+	if s.tls.enabled() {
+		tlsConfig, err := s.tls.tlsConfig()
+		if err != nil {
+			s.logger.Fatal().Msgf("Failed to configure TLS... %+v", err)
+		}
+
+		server.TLSConfig = tlsConfig
+	}
+
+	handler := server.Handler
+	if err := s.protocols.configure(server, s.tls.enabled()); err != nil {
+		s.logger.Fatal().Msgf("Failed to configure HTTP protocols... %+v", err)
+	}
+
+	for _, address := range addresses {
+		s.listenAndServe(server, address)
+	}
+
+	if err := s.protocols.startHTTP3(s.restart, server.Addr, handler, server.TLSConfig); err != nil {
+		s.logger.Fatal().Msgf("Failed to start HTTP/3 server... listen: %+v\n", err)
+	}
+
+	s.restart.notifyReady()
 	s.shutdownGracefully(server)
 }
 
+func (s *serverService) App() *App {
+	return s.app
+}
+
+func (s *serverService) Lifecycle() LifecycleService {
+	return s.app.Lifecycle()
+}
+
+// initialize wires the server once and returns the startup validation or start hook error instead of
+// exiting, so Start and in-process callers of Handler decide how to fail.
+func (s *serverService) initialize() error {
+	s.initializeOnce.Do(func() {
+		s.resolveServices()
+		s.installLogBridge()
+		s.registerConfigBindings()
+
+		if s.initializeErr = s.validateConfig(); s.initializeErr != nil {
+			return
+		}
+
+		s.app.activate(func() {
+			s.initializeServer(s.routes, s.database)
+
+			if s.initializeErr = s.validateConfig(); s.initializeErr == nil {
+				s.initializeErr = s.runStartHooks()
+			}
+		})
+	})
+
+	return s.initializeErr
+}
+
+// Handler wires the server without listening and returns its router, or the error that would have
+// stopped Start.
+func (s *serverService) Handler() (http.Handler, error) {
+	if err := s.initialize(); err != nil {
+		return nil, err
+	}
+
+	return s.router.Handler(), nil
+}
+
+func (s *serverService) Stop(ctx context.Context) error {
+	return s.app.lifecycleService().stop(ctx)
+}
+
 func (s *serverService) Start() {
 	s.cmd.Run = func(_ *cobra.Command, args []string) {
-		s.initializeServer(s.routes, s.database)
+		if err := s.initialize(); err != nil {
+			s.logger.Fatal().Msgf("Server failed to start: %+v", err)
+		}
+
 		s.startServer()
 	}
 
@@ -0,0 +1,94 @@
+// Unpublished Work © 2024
+
+package sfktest
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type BoomBody struct {
+	StatusCode      int       `json:"statusCode"`
+	Error           string    `json:"error"`
+	Message         string    `json:"message"`
+	ValidationError string    `json:"validationError"`
+	TraceId         string    `json:"traceId"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+func DecodeBoom(t testing.TB, recorder *httptest.ResponseRecorder) BoomBody {
+	t.Helper()
+
+	var body BoomBody
+	if err := jsoniter.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("sfktest: response body is not a boom exception: %+v, body: %s", err, recorder.Body.String())
+	}
+
+	return body
+}
+
+func AssertBoom(t testing.TB, recorder *httptest.ResponseRecorder, statusCode int, message string) BoomBody {
+	t.Helper()
+
+	if recorder.Code != statusCode {
+		t.Fatalf("sfktest: expected status %d, got %d with body %s", statusCode, recorder.Code, recorder.Body.String())
+	}
+
+	body := DecodeBoom(t, recorder)
+
+	if body.StatusCode != statusCode {
+		t.Errorf("sfktest: expected boom statusCode %d, got %d", statusCode, body.StatusCode)
+	}
+
+	if message != "" && body.Message != message {
+		t.Errorf("sfktest: expected boom message %q, got %q", message, body.Message)
+	}
+
+	return body
+}
+
+func AssertStatus(t testing.TB, recorder *httptest.ResponseRecorder, statusCode int) {
+	t.Helper()
+
+	if recorder.Code != statusCode {
+		t.Fatalf("sfktest: expected status %d, got %d with body %s", statusCode, recorder.Code, recorder.Body.String())
+	}
+}
+
+func (h *Harness) FindLogs(level, messageContains string) []LogLine {
+	var matches []LogLine
+
+	for _, line := range h.Logs() {
+		if level != "" && line.Level() != level {
+			continue
+		}
+
+		if strings.Contains(line.Message(), messageContains) {
+			matches = append(matches, line)
+		}
+	}
+
+	return matches
+}
+
+func (h *Harness) AssertLogged(level, messageContains string) LogLine {
+	h.t.Helper()
+
+	matches := h.FindLogs(level, messageContains)
+	if len(matches) == 0 {
+		h.t.Fatalf("sfktest: expected a %q log containing %q, got %d lines", level, messageContains, len(h.Logs()))
+	}
+
+	return matches[0]
+}
+
+func (h *Harness) AssertNotLogged(level, messageContains string) {
+	h.t.Helper()
+
+	if matches := h.FindLogs(level, messageContains); len(matches) != 0 {
+		h.t.Fatalf("sfktest: expected no %q log containing %q, got %+v", level, messageContains, matches)
+	}
+}
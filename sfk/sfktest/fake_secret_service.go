@@ -0,0 +1,89 @@
+// Unpublished Work © 2024
+
+package sfktest
+
+import (
+	"fmt"
+	"github.com/omkarsrepo/server-framework/sfk"
+	"github.com/omkarsrepo/server-framework/sfk/boom"
+	"github.com/omkarsrepo/server-framework/sfk/password"
+	"sync"
+)
+
+type FakeSecretService struct {
+	config  sfk.ConfigService
+	secrets map[string]string
+	mtx     sync.RWMutex
+}
+
+func NewFakeSecretService(config sfk.ConfigService, secrets map[string]string) *FakeSecretService {
+	values := make(map[string]string, len(secrets))
+	for name, value := range secrets {
+		values[name] = value
+	}
+
+	return &FakeSecretService{
+		config:  config,
+		secrets: values,
+	}
+}
+
+func (f *FakeSecretService) ValueOf(secretKey string) (string, boom.Exception) {
+	secretName := secretKey
+	if f.config != nil {
+		secretName = f.config.GetViper().GetString(secretKey)
+	}
+
+	value, exp := f.Resolve(secretName)
+	if exp != nil {
+		return "", boom.NotFound(fmt.Sprintf("Secret %s not found for key %s", secretName, secretKey))
+	}
+
+	return value, nil
+}
+
+func (f *FakeSecretService) Resolve(secretName string) (string, boom.Exception) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	value, found := f.secrets[secretName]
+	if !found {
+		return "", boom.NotFound(fmt.Sprintf("Secret %s not found", secretName))
+	}
+
+	return value, nil
+}
+
+func (f *FakeSecretService) Create(secretName string, value ...string) (string, boom.Exception) {
+	secretValue := password.Generate()
+
+	if len(value) != 0 {
+		secretValue = value[0]
+	}
+
+	f.Set(secretName, secretValue)
+
+	return secretValue, nil
+}
+
+func (f *FakeSecretService) PurgeSecretsCache() {}
+
+func (f *FakeSecretService) Delete(secretName string) boom.Exception {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if _, found := f.secrets[secretName]; !found {
+		return boom.NotFound(fmt.Sprintf("Secret %s not found", secretName))
+	}
+
+	delete(f.secrets, secretName)
+
+	return nil
+}
+
+func (f *FakeSecretService) Set(secretName, value string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.secrets[secretName] = value
+}
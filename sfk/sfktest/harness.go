@@ -0,0 +1,179 @@
+// Unpublished Work © 2024
+
+package sfktest
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/omkarsrepo/server-framework/sfk"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var defaultConfig = map[string]any{
+	"env":                  "test",
+	"rateLimitCallsPerSec": 1000,
+	"gracefulShutdownSecs": 1,
+}
+
+type Options struct {
+	Config        map[string]any
+	Secrets       map[string]string
+	ServerOptions *types.ServerOptions
+	Routes        func(router *gin.Engine)
+}
+
+type Harness struct {
+	t       testing.TB
+	app     *sfk.App
+	server  sfk.ServerService
+	handler http.Handler
+	secrets *FakeSecretService
+	logs    *logCapture
+}
+
+func mergedConfig(config map[string]any) map[string]any {
+	merged := make(map[string]any, len(defaultConfig)+len(config))
+
+	for key, value := range defaultConfig {
+		merged[key] = value
+	}
+
+	for key, value := range config {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// New wires the full router and middleware stack from options.ServerOptions without listening, on an
+// in-memory config merged over test defaults, a FakeSecretService and a captured JSON logger. The service's
+// own ServerOptions.Routes can be passed unchanged: the package-level sfk accessors called from it resolve
+// to the harness App. A failing startup validation or start hook fails the test.
+func New(t testing.TB, options *Options) *Harness {
+	t.Helper()
+
+	if options == nil {
+		options = &Options{}
+	}
+
+	serverOptions := options.ServerOptions
+	if serverOptions == nil {
+		serverOptions = &types.ServerOptions{}
+	}
+
+	logs := &logCapture{}
+	secrets := NewFakeSecretService(nil, options.Secrets)
+
+	app := sfk.NewAppWithOptions(&sfk.AppOptions{
+		Config:        mergedConfig(options.Config),
+		SecretService: secrets,
+		LogWriter:     logs,
+	})
+
+	server := sfk.NewServerServiceWithApp(app, "sfktest", "sfktest harness", serverOptions)
+	secrets.config = app.Config()
+	handler, err := server.Handler()
+	if err != nil {
+		t.Fatalf("sfktest: server failed to start: %+v", err)
+	}
+
+	if options.Routes != nil {
+		options.Routes(app.Router().Router())
+	}
+
+	t.Cleanup(func() {
+		if err := server.Stop(context.Background()); err != nil {
+			t.Errorf("sfktest: lifecycle stop hooks failed: %+v", err)
+		}
+	})
+
+	return &Harness{
+		t:       t,
+		app:     app,
+		server:  server,
+		handler: handler,
+		secrets: secrets,
+		logs:    logs,
+	}
+}
+
+func (h *Harness) App() *sfk.App {
+	return h.app
+}
+
+func (h *Harness) Router() *gin.Engine {
+	return h.app.Router().Router()
+}
+
+func (h *Harness) Secrets() *FakeSecretService {
+	return h.secrets
+}
+
+func (h *Harness) Do(req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	h.handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func (h *Harness) Request(method, path string, body any, headers map[string]string) *httptest.ResponseRecorder {
+	h.t.Helper()
+
+	var reader io.Reader
+	switch value := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(value)
+	case []byte:
+		reader = bytes.NewReader(value)
+	default:
+		encoded, err := jsoniter.Marshal(value)
+		if err != nil {
+			h.t.Fatalf("sfktest: failed to encode request body: %+v", err)
+		}
+
+		reader = bytes.NewReader(encoded)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	return h.Do(req)
+}
+
+func (h *Harness) GET(path string) *httptest.ResponseRecorder {
+	return h.Request(http.MethodGet, path, nil, nil)
+}
+
+func (h *Harness) POST(path string, body any) *httptest.ResponseRecorder {
+	return h.Request(http.MethodPost, path, body, nil)
+}
+
+func (h *Harness) PUT(path string, body any) *httptest.ResponseRecorder {
+	return h.Request(http.MethodPut, path, body, nil)
+}
+
+func (h *Harness) DELETE(path string) *httptest.ResponseRecorder {
+	return h.Request(http.MethodDelete, path, nil, nil)
+}
+
+func (h *Harness) Logs() []LogLine {
+	return h.logs.lines()
+}
+
+func (h *Harness) ResetLogs() {
+	h.logs.reset()
+}
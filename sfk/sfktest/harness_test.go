@@ -0,0 +1,135 @@
+// Unpublished Work © 2024
+
+package sfktest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/omkarsrepo/server-framework/sfk"
+	"github.com/omkarsrepo/server-framework/sfk/sfktest"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type greetingController struct {
+	greeting string
+	logger   sfk.LoggerService
+}
+
+func (g *greetingController) greet(ginCtx *gin.Context) {
+	g.logger.Info(ginCtx).Msg("Greeting sent")
+	ginCtx.String(http.StatusOK, g.greeting)
+}
+
+func serverOptions() *types.ServerOptions {
+	return &types.ServerOptions{
+		Routes: func() {
+			controller := &greetingController{
+				greeting: sfk.ConfigServiceInstance().GetString("greeting"),
+				logger:   sfk.LoggerServiceInstance(),
+			}
+
+			sfk.RouterInstance().Router().GET("/greeting", controller.greet)
+		},
+	}
+}
+
+func TestHarnessServesServerOptionsRoutes(t *testing.T) {
+	harness := sfktest.New(t, &sfktest.Options{
+		Config:        map[string]any{"greeting": "hello from the harness"},
+		ServerOptions: serverOptions(),
+	})
+
+	recorder := harness.GET("/greeting")
+
+	sfktest.AssertStatus(t, recorder, http.StatusOK)
+	if recorder.Body.String() != "hello from the harness" {
+		t.Errorf("expected the harness config greeting, got %q", recorder.Body.String())
+	}
+
+	line := harness.AssertLogged("info", "Greeting sent")
+	if line.TraceId() == "" {
+		t.Errorf("expected the request log line to carry a traceId, got %v", line)
+	}
+}
+
+func TestHarnessesAreIsolated(t *testing.T) {
+	first := sfktest.New(t, &sfktest.Options{
+		Config:        map[string]any{"greeting": "first"},
+		ServerOptions: serverOptions(),
+	})
+	second := sfktest.New(t, &sfktest.Options{
+		Config:        map[string]any{"greeting": "second"},
+		ServerOptions: serverOptions(),
+	})
+
+	if body := first.GET("/greeting").Body.String(); body != "first" {
+		t.Errorf("expected the first harness greeting, got %q", body)
+	}
+
+	if body := second.GET("/greeting").Body.String(); body != "second" {
+		t.Errorf("expected the second harness greeting, got %q", body)
+	}
+
+	if lines := first.FindLogs("info", "Greeting sent"); len(lines) != 1 {
+		t.Errorf("expected one greeting log line in the first harness, got %d", len(lines))
+	}
+}
+
+type fatalRecorder struct {
+	testing.TB
+	message string
+}
+
+func (f *fatalRecorder) Fatalf(format string, args ...any) {
+	f.message = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+func TestHarnessFailsTheTestWhenTheServerFailsToStart(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     *sfktest.Options
+		wantMessage string
+	}{
+		{
+			name:        "invalid configuration",
+			options:     &sfktest.Options{Config: map[string]any{"log": map[string]any{"format": "xml"}}},
+			wantMessage: "invalid configuration",
+		},
+		{
+			name: "failing start hook",
+			options: &sfktest.Options{ServerOptions: &types.ServerOptions{
+				LifecycleHooks: []types.LifecycleHook{{
+					Name: "database",
+					OnStart: func(context.Context) error {
+						return errors.New("database unreachable")
+					},
+				}},
+			}},
+			wantMessage: "database unreachable",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := &fatalRecorder{TB: t}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				sfktest.New(recorder, test.options)
+			}()
+			<-done
+
+			if !strings.Contains(recorder.message, test.wantMessage) {
+				t.Errorf("expected the harness to fail the test with %q, got %q", test.wantMessage, recorder.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Unpublished Work © 2024
+
+package sfktest
+
+import (
+	"bufio"
+	"bytes"
+	jsoniter "github.com/json-iterator/go"
+	"sync"
+)
+
+type LogLine map[string]any
+
+type logCapture struct {
+	buffer bytes.Buffer
+	mtx    sync.Mutex
+}
+
+func (l *logCapture) Write(p []byte) (int, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.buffer.Write(p)
+}
+
+func (l *logCapture) lines() []LogLine {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	var lines []LogLine
+
+	scanner := bufio.NewScanner(bytes.NewReader(l.buffer.Bytes()))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		var line LogLine
+		if err := jsoniter.Unmarshal(scanner.Bytes(), &line); err == nil {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func (l *logCapture) reset() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.buffer.Reset()
+}
+
+func (l LogLine) Level() string {
+	level, _ := l["level"].(string)
+	return level
+}
+
+func (l LogLine) Message() string {
+	message, _ := l["message"].(string)
+	return message
+}
+
+func (l LogLine) TraceId() string {
+	traceId, _ := l["traceId"].(string)
+	return traceId
+}
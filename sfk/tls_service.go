@@ -0,0 +1,365 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/omkarsrepo/server-framework/sfk/boom"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+type TLSService interface {
+	enabled() bool
+	tlsConfig() (*tls.Config, error)
+	close()
+}
+
+type uncachedSecretService interface {
+	freshValueOf(secretKey string) (string, boom.Exception)
+}
+
+type tlsService struct {
+	app          *App
+	options      *types.TLSOptions
+	logger       *zerolog.Logger
+	current      atomic.Pointer[tls.Config]
+	watcher      *fsnotify.Watcher
+	stopRefresh  chan struct{}
+	closeRefresh sync.Once
+}
+
+func tlsOptionsFromConfig(config ConfigService) *types.TLSOptions {
+	return &types.TLSOptions{
+		CertFile:           config.GetString("tls.certFile"),
+		KeyFile:            config.GetString("tls.keyFile"),
+		CertSecretKey:      lo.Ternary(config.GetString("tls.certSecret") != "", "tls.certSecret", ""),
+		KeySecretKey:       lo.Ternary(config.GetString("tls.keySecret") != "", "tls.keySecret", ""),
+		ClientCAFile:       config.GetString("tls.clientCaFile"),
+		OptionalClientCert: config.GetBool("tls.optionalClientCert"),
+		MinVersion:         config.GetString("tls.minVersion"),
+		CipherSuites:       config.GetStringSlice("tls.cipherSuites"),
+	}
+}
+
+func newTLSService(app *App, options *types.TLSOptions) TLSService {
+	if options == nil {
+		options = tlsOptionsFromConfig(app.Config())
+	}
+
+	return &tlsService{
+		app:     app,
+		options: options,
+		logger:  app.Logger().ZeroLogger(),
+	}
+}
+
+func (t *tlsService) enabled() bool {
+	return (t.options.CertFile != "" && t.options.KeyFile != "") ||
+		(t.options.CertSecretKey != "" && t.options.KeySecretKey != "")
+}
+
+func (t *tlsService) usesFiles() bool {
+	return t.options.CertFile != "" && t.options.KeyFile != ""
+}
+
+// secretValue reads TLS secrets past the secret cache, so a rotated certificate is picked up within one
+// tls.secretRefresh instead of up to a cache TTL later.
+func (t *tlsService) secretValue(secretKey string) (string, boom.Exception) {
+	secretService := t.app.Secrets()
+	if uncached, ok := secretService.(uncachedSecretService); ok {
+		return uncached.freshValueOf(secretKey)
+	}
+
+	return secretService.ValueOf(secretKey)
+}
+
+func (t *tlsService) loadCertificate() (tls.Certificate, error) {
+	if t.usesFiles() {
+		return tls.LoadX509KeyPair(t.options.CertFile, t.options.KeyFile)
+	}
+
+	certPem, exp := t.secretValue(t.options.CertSecretKey)
+	if exp != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to fetch TLS certificate secret: %w", exp)
+	}
+
+	keyPem, exp := t.secretValue(t.options.KeySecretKey)
+	if exp != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to fetch TLS key secret: %w", exp)
+	}
+
+	return tls.X509KeyPair([]byte(certPem), []byte(keyPem))
+}
+
+func (t *tlsService) loadClientCAs() (*x509.CertPool, error) {
+	caPem, err := os.ReadFile(t.options.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file %s: %w", t.options.ClientCAFile, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPem) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", t.options.ClientCAFile)
+	}
+
+	return pool, nil
+}
+
+func minTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return tls.VersionTLS12, nil
+	}
+
+	tlsVersion, found := tlsVersions[version]
+	if !found {
+		return 0, fmt.Errorf(`unsupported TLS min version "%s", can be "1.0", "1.1", "1.2" or "1.3"`, version)
+	}
+
+	return tlsVersion, nil
+}
+
+func cipherSuiteIds(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	suites := append(tls.CipherSuites(), tls.InsecureCipherSuites()...)
+	suitesByName := lo.SliceToMap(suites, func(suite *tls.CipherSuite) (string, uint16) {
+		return suite.Name, suite.ID
+	})
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, found := suitesByName[strings.TrimSpace(name)]
+		if !found {
+			return nil, fmt.Errorf(`unsupported TLS cipher suite "%s"`, name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (t *tlsService) buildConfig() (*tls.Config, error) {
+	certificate, err := t.loadCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	minVersion, err := minTLSVersion(t.options.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherSuites, err := cipherSuiteIds(t.options.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if t.options.ClientCAFile != "" {
+		clientCAs, err := t.loadClientCAs()
+		if err != nil {
+			return nil, err
+		}
+
+		config.ClientCAs = clientCAs
+		config.ClientAuth = lo.Ternary(t.options.OptionalClientCert, tls.VerifyClientCertIfGiven, tls.RequireAndVerifyClientCert)
+	}
+
+	return config, nil
+}
+
+func (t *tlsService) reload() {
+	config, err := t.buildConfig()
+	if err != nil {
+		t.logger.Error().Err(err).Msg("Failed to reload TLS certificates, keeping the previous ones")
+		return
+	}
+
+	t.current.Store(config)
+	t.logger.Info().Msg("TLS certificates reloaded successfully")
+}
+
+func (t *tlsService) watchedFiles() []string {
+	return lo.Compact([]string{t.options.CertFile, t.options.KeyFile, t.options.ClientCAFile})
+}
+
+func (t *tlsService) isWatchedEvent(event fsnotify.Event) bool {
+	name := filepath.Base(event.Name)
+	if strings.HasPrefix(name, "..data") {
+		return true
+	}
+
+	return lo.SomeBy(t.watchedFiles(), func(file string) bool {
+		return filepath.Clean(file) == filepath.Clean(event.Name)
+	})
+}
+
+func (t *tlsService) watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	dirs := lo.Uniq(lo.Map(t.watchedFiles(), func(file string, _ int) string {
+		return filepath.Dir(file)
+	}))
+
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return err
+		}
+	}
+
+	t.watcher = watcher
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if t.isWatchedEvent(event) && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename)) {
+					t.reload()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				t.logger.Error().Err(err).Msg("TLS certificate watcher failed")
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (t *tlsService) secretRefreshInterval() time.Duration {
+	config := t.app.Config()
+
+	interval := config.GetDurationOrDefault("tls.secretRefresh", rawDurationOrDefault(config, "secrets.cacheTTL", defaultSecretsCacheTTL))
+	if interval <= 0 {
+		return defaultSecretsCacheTTL
+	}
+
+	return interval
+}
+
+func sameCertificate(current *tls.Config, next *tls.Config) bool {
+	if current == nil || len(current.Certificates) == 0 || len(next.Certificates) == 0 {
+		return false
+	}
+
+	return slices.EqualFunc(current.Certificates[0].Certificate, next.Certificates[0].Certificate, bytes.Equal)
+}
+
+func (t *tlsService) refreshSecret() {
+	config, err := t.buildConfig()
+	if err != nil {
+		t.logger.Error().Err(err).Msg("Failed to refresh TLS certificate secrets, keeping the previous ones")
+		return
+	}
+
+	if sameCertificate(t.current.Load(), config) {
+		return
+	}
+
+	t.current.Store(config)
+	t.logger.Info().Msg("TLS certificates refreshed from secrets")
+}
+
+func (t *tlsService) refreshSecrets() {
+	interval := t.secretRefreshInterval()
+	t.stopRefresh = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				t.refreshSecret()
+			case <-t.stopRefresh:
+				return
+			}
+		}
+	}()
+}
+
+func (t *tlsService) tlsConfig() (*tls.Config, error) {
+	if !t.enabled() {
+		return nil, errors.New("TLS is not configured, set both certificate and key")
+	}
+
+	config, err := t.buildConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	t.current.Store(config)
+
+	if t.usesFiles() {
+		if err := t.watch(); err != nil {
+			return nil, fmt.Errorf("failed to watch TLS certificate files: %w", err)
+		}
+	} else {
+		t.refreshSecrets()
+	}
+
+	return &tls.Config{
+		MinVersion: config.MinVersion,
+		NextProtos: config.NextProtos,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &t.current.Load().Certificates[0], nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return t.current.Load(), nil
+		},
+	}, nil
+}
+
+func (t *tlsService) close() {
+	if t.watcher != nil {
+		_ = t.watcher.Close()
+	}
+
+	if t.stopRefresh != nil {
+		t.closeRefresh.Do(func() {
+			close(t.stopRefresh)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Unpublished Work © 2024
+
+package sfk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/omkarsrepo/server-framework/sfk/boom"
+	"github.com/omkarsrepo/server-framework/sfk/types"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	cert string
+	key  string
+}
+
+func newTestCertificate(t *testing.T, serial int64) testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate a key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create a certificate: %v", err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal the key: %v", err)
+	}
+
+	return testCertificate{
+		cert: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		key:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})),
+	}
+}
+
+func servedSerial(t *testing.T, config *tls.Config) int64 {
+	t.Helper()
+
+	certificate, err := config.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("failed to get the served certificate: %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse the served certificate: %v", err)
+	}
+
+	return leaf.SerialNumber.Int64()
+}
+
+type mapSecretService struct {
+	config  ConfigService
+	secrets map[string]string
+}
+
+func (m *mapSecretService) ValueOf(secretKey string) (string, boom.Exception) {
+	value, found := m.secrets[m.config.GetString(secretKey)]
+	if !found {
+		return "", boom.NotFound("secret not found")
+	}
+
+	return value, nil
+}
+
+func (m *mapSecretService) Create(secretName string, value ...string) (string, boom.Exception) {
+	m.secrets[secretName] = value[0]
+	return value[0], nil
+}
+
+func (m *mapSecretService) PurgeSecretsCache() {}
+
+func (m *mapSecretService) Delete(secretName string) boom.Exception {
+	delete(m.secrets, secretName)
+	return nil
+}
+
+func TestTLSServiceReloadsFiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		rotate     func(certFile, keyFile string, next testCertificate)
+		wantSerial int64
+	}{
+		{
+			name: "rotated certificate",
+			rotate: func(certFile, keyFile string, next testCertificate) {
+				_ = os.WriteFile(certFile, []byte(next.cert), 0600)
+				_ = os.WriteFile(keyFile, []byte(next.key), 0600)
+			},
+			wantSerial: 2,
+		},
+		{
+			name: "invalid certificate keeps the previous one",
+			rotate: func(certFile, keyFile string, next testCertificate) {
+				_ = os.WriteFile(certFile, []byte("not a certificate"), 0600)
+			},
+			wantSerial: 1,
+		},
+		{
+			name: "half written pair keeps the previous one",
+			rotate: func(certFile, keyFile string, next testCertificate) {
+				_ = os.WriteFile(certFile, []byte(next.cert), 0600)
+			},
+			wantSerial: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+			first := newTestCertificate(t, 1)
+			if err := os.WriteFile(certFile, []byte(first.cert), 0600); err != nil {
+				t.Fatalf("failed to write the certificate: %v", err)
+			}
+			if err := os.WriteFile(keyFile, []byte(first.key), 0600); err != nil {
+				t.Fatalf("failed to write the key: %v", err)
+			}
+
+			app := NewAppWithOptions(&AppOptions{Config: map[string]any{}, LogWriter: io.Discard})
+			service := newTLSService(app, &types.TLSOptions{CertFile: certFile, KeyFile: keyFile}).(*tlsService)
+
+			config, err := service.tlsConfig()
+			if err != nil {
+				t.Fatalf("failed to load the TLS config: %v", err)
+			}
+			defer service.close()
+
+			test.rotate(certFile, keyFile, newTestCertificate(t, 2))
+			service.reload()
+
+			if serial := servedSerial(t, config); serial != test.wantSerial {
+				t.Errorf("expected certificate %d to be served, got %d", test.wantSerial, serial)
+			}
+		})
+	}
+}
+
+func TestTLSServiceRefreshesSecrets(t *testing.T) {
+	tests := []struct {
+		name       string
+		rotate     func(secrets map[string]string, next testCertificate)
+		wantSerial int64
+	}{
+		{
+			name: "rotated secrets",
+			rotate: func(secrets map[string]string, next testCertificate) {
+				secrets["tls-cert"], secrets["tls-key"] = next.cert, next.key
+			},
+			wantSerial: 2,
+		},
+		{
+			name: "unavailable secret keeps the previous certificate",
+			rotate: func(secrets map[string]string, next testCertificate) {
+				delete(secrets, "tls-key")
+			},
+			wantSerial: 1,
+		},
+		{
+			name:       "unchanged secrets",
+			rotate:     func(secrets map[string]string, next testCertificate) {},
+			wantSerial: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			first := newTestCertificate(t, 1)
+			secrets := &mapSecretService{secrets: map[string]string{"tls-cert": first.cert, "tls-key": first.key}}
+
+			app := NewAppWithOptions(&AppOptions{
+				Config:        map[string]any{"tls": map[string]any{"certSecret": "tls-cert", "keySecret": "tls-key", "secretRefresh": "1h"}},
+				SecretService: secrets,
+				LogWriter:     io.Discard,
+			})
+			secrets.config = app.Config()
+
+			service := newTLSService(app, nil).(*tlsService)
+
+			config, err := service.tlsConfig()
+			if err != nil {
+				t.Fatalf("failed to load the TLS config: %v", err)
+			}
+			defer service.close()
+
+			test.rotate(secrets.secrets, newTestCertificate(t, 2))
+			service.refreshSecret()
+
+			if serial := servedSerial(t, config); serial != test.wantSerial {
+				t.Errorf("expected certificate %d to be served, got %d", test.wantSerial, serial)
+			}
+		})
+	}
+}
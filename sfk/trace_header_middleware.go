@@ -18,6 +18,9 @@ func applyTraceHeader() gin.HandlerFunc {
 			ginCtx.Set("TRACE_ID", traceId)
 		}
 
+		logger := newRequestLogger(ginCtx, appFromContext(ginCtx).Logger().ZeroLogger(), ginCtx.GetString("TRACE_ID"))
+		ginCtx.Request = ginCtx.Request.WithContext(WithLogger(ginCtx.Request.Context(), logger))
+
 		ginCtx.Next()
 	}
 }
@@ -0,0 +1,22 @@
+// Unpublished Work © 2024
+
+package types
+
+const (
+	ConfigKeyTypeString   = "string"
+	ConfigKeyTypeInteger  = "integer"
+	ConfigKeyTypeNumber   = "number"
+	ConfigKeyTypeBoolean  = "boolean"
+	ConfigKeyTypeDuration = "duration"
+	ConfigKeyTypeArray    = "array"
+	ConfigKeyTypeObject   = "object"
+)
+
+type ConfigKey struct {
+	Key         string
+	Type        string
+	Default     any
+	Description string
+	Required    bool
+	Enum        []string
+}
@@ -0,0 +1,28 @@
+// Unpublished Work © 2024
+
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// ConfigSource provides centrally managed config, passed as ServerOptions.ConfigSources and merged over the
+// config files, below env vars, flags and --set. Sources must be given before the config is first read,
+// NewServerService panics otherwise. Watch calls onChange when Load would return new settings.
+type ConfigSource interface {
+	Name() string
+	Load(ctx context.Context) (map[string]any, error)
+	Watch(ctx context.Context, onChange func()) error
+}
+
+// HTTPConfigSourceOptions configure sfk.NewHTTPConfigSource, which fetches a JSON object from URL and polls
+// it every PollInterval. The last good response is written to CacheFile so startup survives the remote
+// being down. Failed polls are logged at most every 5 minutes, and once more on recovery.
+type HTTPConfigSourceOptions struct {
+	URL          string
+	Headers      map[string]string
+	Timeout      time.Duration
+	PollInterval time.Duration
+	CacheFile    string
+}
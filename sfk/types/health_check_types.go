@@ -0,0 +1,16 @@
+// Unpublished Work © 2024
+
+package types
+
+import (
+	"context"
+	"time"
+)
+
+type HealthCheck struct {
+	Name     string
+	Timeout  time.Duration
+	Critical bool
+	Liveness bool
+	Check    func(ctx context.Context) error
+}
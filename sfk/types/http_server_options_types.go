@@ -0,0 +1,24 @@
+// Unpublished Work © 2024
+
+package types
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// HTTPServerOptions override the server.* config of the main server. Zero values keep the config value,
+// a negative timeout disables it and a negative MaxConnections removes the connection limit. Disable the
+// write timeout for SSE, streaming responses and long pprof profiles. ConnStateHook runs on every
+// connection state change, next to server.logConnectionState.
+type HTTPServerOptions struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	DisableKeepAlives bool
+	MaxConnections    int
+	ConnStateHook     func(conn net.Conn, state http.ConnState)
+}
@@ -0,0 +1,12 @@
+// Unpublished Work © 2024
+
+package types
+
+import "context"
+
+type LifecycleHook struct {
+	Name     string
+	Priority int
+	OnStart  func(ctx context.Context) error
+	OnStop   func(ctx context.Context) error
+}
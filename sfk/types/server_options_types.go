@@ -4,6 +4,21 @@ package types
 
 import "github.com/gin-gonic/gin"
 
+// ServerOptions configures the server created by sfk.NewServerService.
+//
+// ListenAddresses, like listen, are all served by the same router: host:port, tcp://host:port or
+// unix:/run/app.sock?mode=0660. Stale unix socket files are removed on start, and without any address the
+// server binds :<port>.
+//
+// EnableAdminServer, like admin.enabled, moves pprof, /livez and /readyz off the main router onto
+// admin.host:admin.port, next to the /admin endpoints (config, cache, featureFlags, logLevels and logSinks)
+// authorized by pprofSecret. The admin server has the server.* limits but no write timeout.
+//
+// EnvPrefix enables env var overrides: with "APP", APP_RATELIMITCALLSPERSEC overrides rateLimitCallsPerSec
+// and a double underscore separates nested keys, so APP_HASHICORP__ORGANIZATIONID overrides
+// hashicorp.organizationId. Env vars without the prefix are ignored, and no env overrides are read when
+// it is empty. Lists and maps can be given as JSON, lists also comma separated. Precedence, lowest first:
+// defaults < config files < config sources < env vars < flags < --set.
 type ServerOptions struct {
 	Routes                         func()
 	Database                       func()
@@ -17,4 +32,14 @@ type ServerOptions struct {
 	SkipTraceHeaderMiddleware      bool
 	SkipRequestLoggerMiddleware    bool
 	DisablePprof                   bool
+	TLS                            *TLSOptions
+	HTTPServer                     *HTTPServerOptions
+	ListenAddresses                []string
+	EnableH2C                      bool
+	EnableHTTP3                    bool
+	EnableAdminServer              bool
+	HealthChecks                   []HealthCheck
+	LifecycleHooks                 []LifecycleHook
+	EnvPrefix                      string
+	ConfigSources                  []ConfigSource
 }
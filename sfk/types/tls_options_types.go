@@ -0,0 +1,18 @@
+// Unpublished Work © 2024
+
+package types
+
+// TLSOptions replace the tls.* config when passed as ServerOptions.TLS. TLS is enabled by a certificate
+// and key, read from files or from the PEM secrets named by the config keys CertSecretKey and KeySecretKey.
+// Certificate files are reloaded when they change on disk, secrets are re-fetched every tls.secretRefresh
+// and swapped in when they change. ClientCAFile enables mTLS, optional when OptionalClientCert is set.
+type TLSOptions struct {
+	CertFile           string
+	KeyFile            string
+	CertSecretKey      string
+	KeySecretKey       string
+	ClientCAFile       string
+	OptionalClientCert bool
+	MinVersion         string
+	CipherSuites       []string
+}